@@ -3,6 +3,7 @@ package api
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/oasisprotocol/oasis-core/go/common/errors"
 	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
@@ -117,6 +118,25 @@ type SetableBackend interface {
 	SetEpoch(context.Context, EpochTime) error
 }
 
+// FastForward advances the epoch of a setable backend by the given number of epochs in a single
+// jump and returns the new epoch.
+func FastForward(ctx context.Context, backend SetableBackend, n uint64) (EpochTime, error) {
+	if n == 0 {
+		return EpochInvalid, fmt.Errorf("beacon: fast-forward increment must be non-zero")
+	}
+
+	epoch, err := backend.GetEpoch(ctx, 0) // Latest height.
+	if err != nil {
+		return EpochInvalid, fmt.Errorf("beacon: failed to query current epoch: %w", err)
+	}
+	epoch += EpochTime(n)
+
+	if err = backend.SetEpoch(ctx, epoch); err != nil {
+		return EpochInvalid, err
+	}
+	return epoch, nil
+}
+
 // Genesis is the genesis state.
 type Genesis struct {
 	// Base is the starting epoch.
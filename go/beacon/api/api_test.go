@@ -1,9 +1,15 @@
 package api
 
 import (
+	"context"
+	"fmt"
 	"testing"
+	"time"
 
+	"github.com/eapache/channels"
 	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
 )
 
 func TestDiff(t *testing.T) {
@@ -33,3 +39,197 @@ func TestDiff(t *testing.T) {
 		require.Equal(tc.e1.AbsDiff(tc.e2), tc.diff)
 	}
 }
+
+func TestConsensusParametersSanityCheck(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		params ConsensusParameters
+		valid  bool
+	}{
+		{
+			name: "insecure/zero interval",
+			params: ConsensusParameters{
+				Backend:            BackendInsecure,
+				InsecureParameters: &InsecureParameters{Interval: 0},
+			},
+			valid: false,
+		},
+		{
+			name: "insecure/negative interval",
+			params: ConsensusParameters{
+				Backend:            BackendInsecure,
+				InsecureParameters: &InsecureParameters{Interval: -1},
+			},
+			valid: false,
+		},
+		{
+			name: "insecure/valid interval",
+			params: ConsensusParameters{
+				Backend:            BackendInsecure,
+				InsecureParameters: &InsecureParameters{Interval: 86400},
+			},
+			valid: true,
+		},
+		{
+			name: "vrf/zero interval",
+			params: ConsensusParameters{
+				Backend: BackendVRF,
+				VRFParameters: &VRFParameters{
+					AlphaHighQualityThreshold: 1,
+					ProofSubmissionDelay:      1,
+				},
+			},
+			valid: false,
+		},
+		{
+			name: "vrf/negative interval",
+			params: ConsensusParameters{
+				Backend: BackendVRF,
+				VRFParameters: &VRFParameters{
+					AlphaHighQualityThreshold: 1,
+					Interval:                  -1,
+					ProofSubmissionDelay:      1,
+				},
+			},
+			valid: false,
+		},
+		{
+			name: "vrf/valid interval",
+			params: ConsensusParameters{
+				Backend: BackendVRF,
+				VRFParameters: &VRFParameters{
+					AlphaHighQualityThreshold: 1,
+					Interval:                  20,
+					ProofSubmissionDelay:      5,
+				},
+			},
+			valid: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.params.SanityCheck()
+			if tc.valid {
+				require.NoError(t, err, "SanityCheck")
+			} else {
+				require.Error(t, err, "SanityCheck")
+			}
+		})
+	}
+}
+
+type testEpochBackend struct {
+	Backend
+
+	notifier *pubsub.Broker
+}
+
+func (b *testEpochBackend) WatchEpochs(context.Context) (<-chan EpochTime, pubsub.ClosableSubscription, error) {
+	typedCh := make(chan EpochTime)
+	sub := b.notifier.Subscribe()
+	sub.Unwrap(typedCh)
+
+	return typedCh, sub, nil
+}
+
+func TestRegisterEpochHook(t *testing.T) {
+	require := require.New(t)
+
+	backend := &testEpochBackend{
+		notifier: pubsub.NewBrokerEx(func(ch channels.Channel) {
+			ch.In() <- EpochTime(1)
+		}),
+	}
+
+	recvEpoch := func(ch <-chan EpochTime) EpochTime {
+		select {
+		case epoch := <-ch:
+			return epoch
+		case <-time.After(time.Second):
+			t.Fatalf("failed to receive epoch")
+			return EpochInvalid
+		}
+	}
+
+	var (
+		chs         []chan EpochTime
+		unregisters []func()
+	)
+	for i := 0; i < 2; i++ {
+		ch := make(chan EpochTime, 10)
+		unregister, err := RegisterEpochHook(backend, func(epoch EpochTime) {
+			ch <- epoch
+		})
+		require.NoError(err, "RegisterEpochHook")
+
+		chs = append(chs, ch)
+		unregisters = append(unregisters, unregister)
+	}
+
+	// Both hooks should receive the current epoch followed by the new epoch.
+	backend.notifier.Broadcast(EpochTime(2))
+	for _, ch := range chs {
+		require.Equal(EpochTime(1), recvEpoch(ch))
+		require.Equal(EpochTime(2), recvEpoch(ch))
+	}
+
+	// Unregistered hooks should no longer be invoked.
+	unregisters[0]()
+	unregisters[0]()
+	backend.notifier.Broadcast(EpochTime(3))
+	require.Equal(EpochTime(3), recvEpoch(chs[1]))
+	require.Empty(chs[0], "unregistered hook should not be invoked")
+
+	unregisters[1]()
+}
+
+type testSetableBackend struct {
+	testEpochBackend
+
+	epoch EpochTime
+}
+
+func (b *testSetableBackend) GetEpoch(context.Context, int64) (EpochTime, error) {
+	return b.epoch, nil
+}
+
+func (b *testSetableBackend) SetEpoch(_ context.Context, epoch EpochTime) error {
+	if epoch <= b.epoch {
+		return fmt.Errorf("epoch must be greater than the current epoch")
+	}
+	b.epoch = epoch
+	b.notifier.Broadcast(epoch)
+	return nil
+}
+
+func TestFastForward(t *testing.T) {
+	require := require.New(t)
+
+	backend := &testSetableBackend{
+		testEpochBackend: testEpochBackend{
+			notifier: pubsub.NewBroker(true),
+		},
+		epoch: 1,
+	}
+
+	ch, sub, err := backend.WatchEpochs(context.Background())
+	require.NoError(err, "WatchEpochs")
+	defer sub.Close()
+
+	_, err = FastForward(context.Background(), backend, 0)
+	require.Error(err, "FastForward should reject a zero increment")
+
+	epoch, err := FastForward(context.Background(), backend, 10)
+	require.NoError(err, "FastForward")
+	require.Equal(EpochTime(11), epoch)
+
+	select {
+	case epoch = <-ch:
+		require.Equal(EpochTime(11), epoch, "subscribers should observe the final epoch")
+	case <-time.After(time.Second):
+		t.Fatalf("failed to receive epoch")
+	}
+
+	current, err := backend.GetEpoch(context.Background(), 0)
+	require.NoError(err, "GetEpoch")
+	require.Equal(EpochTime(11), current)
+}
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"context"
+	"sync"
+)
+
+// RegisterEpochHook subscribes to epoch transitions of the given backend and invokes the hook
+// on each transition, starting with the current epoch.
+//
+// Hooks are invoked sequentially from a dedicated goroutine until the returned unregister function
+// is called. The unregister function waits for any in-progress hook invocation to complete, so it
+// must not be called from within the hook itself.
+func RegisterEpochHook(backend Backend, hook func(EpochTime)) (func(), error) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	ch, sub, err := backend.WatchEpochs(ctx)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer sub.Close()
+
+		for {
+			select {
+			case epoch, ok := <-ch:
+				if !ok {
+					return
+				}
+				hook(epoch)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	unregister := func() {
+		once.Do(func() {
+			cancel()
+			<-done
+		})
+	}
+	return unregister, nil
+}
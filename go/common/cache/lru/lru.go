@@ -7,8 +7,14 @@ import (
 	"sync"
 )
 
-// ErrTooLarge is the error returned when a value is too large for the cache.
-var ErrTooLarge = errors.New("lru: value size exceeds maximum capacity")
+var (
+	// ErrTooLarge is the error returned when a value is too large for the cache.
+	ErrTooLarge = errors.New("lru: value size exceeds maximum capacity")
+
+	// ErrBudgetExceeded is the error returned when a value does not fit into the
+	// shared budget even after evicting all other entries from the cache.
+	ErrBudgetExceeded = errors.New("lru: shared budget exceeded")
+)
 
 // Sizeable is the interface implemented by types that support returning their
 // own memory size in bytes.
@@ -35,6 +41,8 @@ type Cache struct {
 	capacityInBytes bool
 	capacity        uint64
 	size            uint64
+
+	budget *Budget
 }
 
 type cacheEntry struct {
@@ -51,9 +59,7 @@ func (c *Cache) Put(key, value interface{}) error {
 	if elem, ok := c.entries[key]; ok {
 		// Key already present in cache.  Evict the existing entry, but do not
 		// call the callback.
-		c.lru.Remove(elem)
-		delete(c.entries, key)
-		c.size -= c.getValueSize(elem.Value.(*cacheEntry).value)
+		c.removeElement(elem)
 	}
 
 	// Sanity check that the value will fit.
@@ -64,6 +70,9 @@ func (c *Cache) Put(key, value interface{}) error {
 	if c.capacity > 0 && c.capacityInBytes && valueSize > c.capacity {
 		return ErrTooLarge
 	}
+	if c.budget != nil && c.budget.limit > 0 && valueSize > c.budget.limit {
+		return ErrTooLarge
+	}
 
 	// Evict entries till there is enough capacity, be it slots or bytes.
 	// The item is guaranteed to fit if enough entries are evicted.
@@ -71,6 +80,18 @@ func (c *Cache) Put(key, value interface{}) error {
 		c.evictEntries(valueSize)
 	}
 
+	// Evict entries till the value fits into the shared budget. Only entries from
+	// this cache can be evicted, so this may fail in case other caches use up
+	// most of the budget.
+	if c.budget != nil {
+		for !c.budget.reserve(valueSize) {
+			if c.lru.Len() == 0 {
+				return ErrBudgetExceeded
+			}
+			c.evictElement(c.lru.Back())
+		}
+	}
+
 	elem := c.lru.PushFront(&cacheEntry{
 		key:   key,
 		value: value,
@@ -100,9 +121,7 @@ func (c *Cache) Remove(key interface{}) bool {
 
 	elem, ok := c.entries[key]
 	if ok {
-		c.lru.Remove(elem)
-		delete(c.entries, key)
-		c.size -= c.getValueSize(elem.Value.(*cacheEntry).value)
+		c.removeElement(elem)
 	}
 
 	return ok
@@ -126,6 +145,9 @@ func (c *Cache) Clear() {
 	c.Lock()
 	defer c.Unlock()
 
+	if c.budget != nil {
+		c.budget.release(c.size)
+	}
 	c.size = 0
 	c.lru = list.New()
 	c.entries = make(map[interface{}]*list.Element)
@@ -157,16 +179,29 @@ func (c *Cache) getEntry(key interface{}, isPeek bool) (interface{}, bool) {
 
 func (c *Cache) evictEntries(targetCapacity uint64) {
 	for c.lru.Len() > 0 && c.capacity-c.size < targetCapacity {
-		elem := c.lru.Back()
-		c.lru.Remove(elem)
+		c.evictElement(c.lru.Back())
+	}
+}
 
+func (c *Cache) evictElement(elem *list.Element) {
+	c.removeElement(elem)
+
+	if c.onEvict != nil {
 		ent := elem.Value.(*cacheEntry)
-		delete(c.entries, ent.key)
-		c.size -= c.getValueSize(ent.value)
+		c.onEvict(ent.key, ent.value)
+	}
+}
 
-		if c.onEvict != nil {
-			c.onEvict(ent.key, ent.value)
-		}
+func (c *Cache) removeElement(elem *list.Element) {
+	c.lru.Remove(elem)
+
+	ent := elem.Value.(*cacheEntry)
+	delete(c.entries, ent.key)
+
+	valueSize := c.getValueSize(ent.value)
+	c.size -= valueSize
+	if c.budget != nil {
+		c.budget.release(valueSize)
 	}
 }
 
@@ -192,6 +227,9 @@ func New(options ...Option) (*Cache, error) {
 			return nil, err
 		}
 	}
+	if c.budget != nil && !c.capacityInBytes {
+		return nil, errors.New("lru: shared budget requires capacity in bytes")
+	}
 
 	return c, nil
 }
@@ -219,3 +257,58 @@ func OnEvict(fn OnEvictFunc) Option {
 		return nil
 	}
 }
+
+// SharedBudget sets the shared budget the new cache will account its size
+// against, in addition to its own capacity. The cache must be configured with
+// a capacity in bytes.
+func SharedBudget(b *Budget) Option {
+	return func(c *Cache) error {
+		c.budget = b
+		return nil
+	}
+}
+
+// Budget is a memory budget that can be shared by multiple caches in order to
+// bound their combined size.
+type Budget struct {
+	sync.Mutex
+
+	limit uint64
+	used  uint64
+}
+
+// Used returns the number of bytes currently used by all caches sharing the
+// budget.
+func (b *Budget) Used() uint64 {
+	b.Lock()
+	defer b.Unlock()
+
+	return b.used
+}
+
+func (b *Budget) reserve(size uint64) bool {
+	b.Lock()
+	defer b.Unlock()
+
+	if b.limit > 0 && size > b.limit-b.used {
+		return false
+	}
+	b.used += size
+	return true
+}
+
+func (b *Budget) release(size uint64) {
+	b.Lock()
+	defer b.Unlock()
+
+	b.used -= size
+}
+
+// NewBudget creates a new shared budget limited to the given number of bytes.
+//
+// If the limit is zero, the budget is unbounded.
+func NewBudget(limit uint64) *Budget {
+	return &Budget{
+		limit: limit,
+	}
+}
@@ -121,6 +121,69 @@ func TestLRUCapacityBytes(t *testing.T) {
 	require.False(ok, "Put - expected entry evicted")
 }
 
+func TestLRUSharedBudget(t *testing.T) {
+	require := require.New(t)
+
+	const budgetSize = 5
+
+	budget := NewBudget(uint64(budgetSize * sha256.Size))
+	cacheA, err := New(
+		Capacity(0, true),
+		SharedBudget(budget),
+	)
+	require.NoError(err, "New")
+	cacheB, err := New(
+		Capacity(0, true),
+		SharedBudget(budget),
+	)
+	require.NoError(err, "New")
+
+	_, err = New(SharedBudget(budget))
+	require.Error(err, "New - budget without capacity in bytes")
+
+	entries := makeEntries(budgetSize)
+	for _, ent := range entries[:3] {
+		err = cacheA.Put(ent.key, ent)
+		require.NoError(err, "Put")
+	}
+	for _, ent := range entries[3:] {
+		err = cacheB.Put(ent.key, ent)
+		require.NoError(err, "Put")
+	}
+	require.EqualValues(budgetSize*sha256.Size, budget.Used(), "Used - budget exhausted")
+
+	// Exceeding the combined budget should evict entries from the inserting cache.
+	newEnt := makeEntry("new entry")
+	err = cacheB.Put(newEnt.key, newEnt)
+	require.NoError(err, "Put - evict")
+	_, ok := cacheB.Peek(entries[3].key)
+	require.False(ok, "Put - expected entry evicted")
+	require.Len(cacheA.Keys(), 3, "Put - other cache should not be affected")
+	require.EqualValues(budgetSize*sha256.Size, budget.Used(), "Used - after eviction")
+
+	// Entries that do not fit into the budget at all should be rejected.
+	hugeEnt := &testEntry{
+		key:   "huge entry - should fail",
+		value: make([]byte, budgetSize*sha256.Size+1),
+	}
+	err = cacheA.Put(hugeEnt.key, hugeEnt)
+	require.ErrorIs(err, ErrTooLarge, "Put - huge entry")
+
+	// Removing entries should release the budget.
+	require.True(cacheA.Remove(entries[0].key), "Remove")
+	require.EqualValues((budgetSize-1)*sha256.Size, budget.Used(), "Used - after removal")
+	cacheB.Clear()
+	require.EqualValues(2*sha256.Size, budget.Used(), "Used - after clear")
+
+	// A cache without any entries cannot make room for new entries.
+	for _, ent := range makeEntries(budgetSize + 2)[budgetSize-1:] {
+		err = cacheA.Put(ent.key, ent)
+		require.NoError(err, "Put")
+	}
+	err = cacheB.Put(newEnt.key, newEnt)
+	require.ErrorIs(err, ErrBudgetExceeded, "Put - budget exceeded")
+}
+
 func TestLRURemoval(t *testing.T) {
 	require := require.New(t)
 
@@ -21,6 +21,8 @@ type Query interface {
 	NodeByConsensusAddress(context.Context, []byte) (*node.Node, error)
 	NodeStatus(context.Context, signature.PublicKey) (*registry.NodeStatus, error)
 	Nodes(context.Context) ([]*node.Node, error)
+	NodesForEntity(context.Context, signature.PublicKey) ([]*node.Node, error)
+	NodesPaged(ctx context.Context, offset, limit uint64) (*registry.NodesPage, error)
 	Runtime(ctx context.Context, id common.Namespace, includeSuspended bool) (*registry.Runtime, error)
 	Runtimes(ctx context.Context, includeSuspended bool) ([]*registry.Runtime, error)
 	Genesis(context.Context) (*registry.Genesis, error)
@@ -103,6 +105,47 @@ func (rq *registryQuerier) Nodes(ctx context.Context) ([]*node.Node, error) {
 	return filteredNodes, nil
 }
 
+func (rq *registryQuerier) NodesForEntity(ctx context.Context, id signature.PublicKey) ([]*node.Node, error) {
+	nodes, err := rq.Nodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	entityNodes := []*node.Node{}
+	for _, n := range nodes {
+		if !n.EntityID.Equal(id) {
+			continue
+		}
+		entityNodes = append(entityNodes, n)
+	}
+	return entityNodes, nil
+}
+
+func (rq *registryQuerier) NodesPaged(ctx context.Context, offset, limit uint64) (*registry.NodesPage, error) {
+	if limit == 0 {
+		return nil, fmt.Errorf("%w: page limit must be positive", registry.ErrInvalidArgument)
+	}
+
+	nodes, err := rq.Nodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &registry.NodesPage{
+		Nodes: []*node.Node{},
+		Total: uint64(len(nodes)),
+	}
+	if offset >= page.Total {
+		return page, nil
+	}
+	end := page.Total
+	if limit < end-offset {
+		end = offset + limit
+	}
+	page.Nodes = nodes[offset:end]
+	return page, nil
+}
+
 func (rq *registryQuerier) Runtime(ctx context.Context, id common.Namespace, includeSuspended bool) (*registry.Runtime, error) {
 	if includeSuspended {
 		return rq.state.AnyRuntime(ctx, id)
@@ -0,0 +1,147 @@
+package registry
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+	abciAPI "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/api"
+	registryState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/registry/state"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+)
+
+func TestQueryNodesForEntity(t *testing.T) {
+	require := require.New(t)
+
+	appState := abciAPI.NewMockApplicationState(&abciAPI.MockApplicationStateConfig{
+		BlockHeight:  1000,
+		CurrentEpoch: 1,
+	})
+	ctx := appState.NewContext(abciAPI.ContextEndBlock)
+	defer ctx.Close()
+
+	state := registryState.NewMutableState(ctx.State())
+
+	// Register two entities with nodes.
+	entityA := memorySigner.NewTestSigner("query test entity A").Public()
+	entityB := memorySigner.NewTestSigner("query test entity B").Public()
+	entityNodes := map[signature.PublicKey][]*node.Node{
+		entityA: nil,
+		entityB: nil,
+	}
+	for i, entityID := range []signature.PublicKey{entityA, entityA, entityB} {
+		nodeSigner := memorySigner.NewTestSigner(fmt.Sprintf("query test node %d", i))
+		consensusSigner := memorySigner.NewTestSigner(fmt.Sprintf("query test node consensus %d", i))
+		nod := &node.Node{
+			Versioned:  cbor.NewVersioned(node.LatestNodeDescriptorVersion),
+			ID:         nodeSigner.Public(),
+			EntityID:   entityID,
+			Expiration: 10,
+			Consensus: node.ConsensusInfo{
+				ID: consensusSigner.Public(),
+			},
+		}
+		sigNode, err := node.MultiSignNode([]signature.Signer{nodeSigner}, registry.RegisterNodeSignatureContext, nod)
+		require.NoError(err, "MultiSignNode")
+		err = state.SetNode(ctx, nil, nod, sigNode)
+		require.NoError(err, "SetNode")
+
+		entityNodes[entityID] = append(entityNodes[entityID], nod)
+	}
+
+	qf := NewQueryFactory(appState)
+	// Need to use blockHeight+1, so that request is treated like it was
+	// made from an ABCI application context.
+	q, err := qf.QueryAt(ctx, 1001)
+	require.NoError(err, "QueryAt")
+
+	for entityID, expected := range entityNodes {
+		registry.SortNodeList(expected)
+
+		nodes, err := q.NodesForEntity(ctx, entityID)
+		require.NoError(err, "NodesForEntity")
+		registry.SortNodeList(nodes)
+		require.EqualValues(expected, nodes, "nodes for entity %s", entityID)
+	}
+
+	// Entities without nodes should get an empty list.
+	nodes, err := q.NodesForEntity(ctx, memorySigner.NewTestSigner("query test entity C").Public())
+	require.NoError(err, "NodesForEntity")
+	require.NotNil(nodes, "nodes for entity without nodes should not be nil")
+	require.Empty(nodes, "nodes for entity without nodes")
+}
+
+func TestQueryNodesPaged(t *testing.T) {
+	require := require.New(t)
+
+	appState := abciAPI.NewMockApplicationState(&abciAPI.MockApplicationStateConfig{
+		BlockHeight:  1000,
+		CurrentEpoch: 1,
+	})
+	ctx := appState.NewContext(abciAPI.ContextEndBlock)
+	defer ctx.Close()
+
+	state := registryState.NewMutableState(ctx.State())
+
+	entityID := memorySigner.NewTestSigner("query test entity").Public()
+	var expected []*node.Node
+	for i := 0; i < 5; i++ {
+		nodeSigner := memorySigner.NewTestSigner(fmt.Sprintf("query test paged node %d", i))
+		consensusSigner := memorySigner.NewTestSigner(fmt.Sprintf("query test paged node consensus %d", i))
+		nod := &node.Node{
+			Versioned:  cbor.NewVersioned(node.LatestNodeDescriptorVersion),
+			ID:         nodeSigner.Public(),
+			EntityID:   entityID,
+			Expiration: 10,
+			Consensus: node.ConsensusInfo{
+				ID: consensusSigner.Public(),
+			},
+		}
+		sigNode, err := node.MultiSignNode([]signature.Signer{nodeSigner}, registry.RegisterNodeSignatureContext, nod)
+		require.NoError(err, "MultiSignNode")
+		err = state.SetNode(ctx, nil, nod, sigNode)
+		require.NoError(err, "SetNode")
+
+		expected = append(expected, nod)
+	}
+	registry.SortNodeList(expected)
+
+	qf := NewQueryFactory(appState)
+	// Need to use blockHeight+1, so that request is treated like it was
+	// made from an ABCI application context.
+	q, err := qf.QueryAt(ctx, 1001)
+	require.NoError(err, "QueryAt")
+
+	// First page.
+	page, err := q.NodesPaged(ctx, 0, 2)
+	require.NoError(err, "NodesPaged")
+	require.EqualValues(5, page.Total, "total")
+	require.EqualValues(expected[0:2], page.Nodes, "first page")
+
+	// Middle page.
+	page, err = q.NodesPaged(ctx, 2, 2)
+	require.NoError(err, "NodesPaged")
+	require.EqualValues(5, page.Total, "total")
+	require.EqualValues(expected[2:4], page.Nodes, "middle page")
+
+	// Last (partial) page.
+	page, err = q.NodesPaged(ctx, 4, 2)
+	require.NoError(err, "NodesPaged")
+	require.EqualValues(expected[4:], page.Nodes, "last page")
+
+	// Offset past the end.
+	page, err = q.NodesPaged(ctx, 10, 2)
+	require.NoError(err, "NodesPaged")
+	require.EqualValues(5, page.Total, "total")
+	require.NotNil(page.Nodes, "nodes past the end should not be nil")
+	require.Empty(page.Nodes, "nodes past the end")
+
+	// Zero limit.
+	_, err = q.NodesPaged(ctx, 0, 0)
+	require.ErrorIs(err, registry.ErrInvalidArgument, "NodesPaged should reject a zero limit")
+}
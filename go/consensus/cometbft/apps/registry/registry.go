@@ -251,7 +251,11 @@ func (app *registryApplication) onRegistryEpochChanged(ctx *api.Context, registr
 
 	// Emit the expired node event for all expired nodes.
 	for _, expiredNode := range expiredNodes {
-		ctx.EmitEvent(api.NewEventBuilder(app.Name()).TypedAttribute(&registry.NodeEvent{Node: expiredNode, IsRegistration: false}))
+		ctx.EmitEvent(api.NewEventBuilder(app.Name()).TypedAttribute(&registry.NodeEvent{
+			Node:           expiredNode,
+			IsRegistration: false,
+			Reason:         registry.DeregistrationReasonExpired,
+		}))
 	}
 	// Emit the node list epoch event.
 	ctx.EmitEvent(api.NewEventBuilder(app.Name()).TypedAttribute(&registry.NodeListEpochEvent{}))
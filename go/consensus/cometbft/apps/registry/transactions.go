@@ -160,7 +160,11 @@ func (app *registryApplication) deregisterEntity(ctx *api.Context, state *regist
 		"entity_id", id,
 	)
 
-	ctx.EmitEvent(api.NewEventBuilder(app.Name()).TypedAttribute(&registry.EntityEvent{Entity: removedEntity, IsRegistration: false}))
+	ctx.EmitEvent(api.NewEventBuilder(app.Name()).TypedAttribute(&registry.EntityEvent{
+		Entity:         removedEntity,
+		IsRegistration: false,
+		Reason:         registry.DeregistrationReasonVoluntary,
+	}))
 
 	return nil
 }
@@ -15,6 +15,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/node"
 	"github.com/oasisprotocol/oasis-core/go/common/quantity"
 	"github.com/oasisprotocol/oasis-core/go/common/version"
+	eventsAPI "github.com/oasisprotocol/oasis-core/go/consensus/api/events"
 	abciAPI "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/api"
 	beaconState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/beacon/state"
 	registryState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/registry/state"
@@ -890,3 +891,99 @@ func TestProofFreshness(t *testing.T) {
 		require.Equal(registry.ErrInvalidArgument, err)
 	})
 }
+
+func TestDeregistrationReason(t *testing.T) {
+	require := requirePkg.New(t)
+
+	cfg := abciAPI.MockApplicationStateConfig{}
+	appState := abciAPI.NewMockApplicationState(&cfg)
+	ctx := appState.NewContext(abciAPI.ContextEndBlock)
+	defer ctx.Close()
+
+	var md abciAPI.NoopMessageDispatcher
+	app := registryApplication{appState, &md}
+	state := registryState.NewMutableState(ctx.State())
+	stakeState := stakingState.NewMutableState(ctx.State())
+
+	err := state.SetConsensusParameters(ctx, &registry.ConsensusParameters{
+		DebugBypassStake: true,
+	})
+	require.NoError(err, "registry.SetConsensusParameters")
+	err = stakeState.SetConsensusParameters(ctx, &staking.ConsensusParameters{
+		DebondingInterval: 1,
+	})
+	require.NoError(err, "staking.SetConsensusParameters")
+
+	// Register an entity with a single node.
+	entitySigner := memorySigner.NewTestSigner("consensus/cometbft/apps/registry: dereg entity signer")
+	nodeSigner := memorySigner.NewTestSigner("consensus/cometbft/apps/registry: dereg node signer")
+	consensusSigner := memorySigner.NewTestSigner("consensus/cometbft/apps/registry: dereg consensus signer")
+
+	ent := entity.Entity{
+		Versioned: cbor.NewVersioned(entity.LatestDescriptorVersion),
+		ID:        entitySigner.Public(),
+		Nodes:     []signature.PublicKey{nodeSigner.Public()},
+	}
+	sigEnt, err := entity.SignEntity(entitySigner, registry.RegisterEntitySignatureContext, &ent)
+	require.NoError(err, "SignEntity")
+	err = state.SetEntity(ctx, &ent, sigEnt)
+	require.NoError(err, "SetEntity")
+
+	nod := node.Node{
+		Versioned:  cbor.NewVersioned(node.LatestNodeDescriptorVersion),
+		ID:         nodeSigner.Public(),
+		EntityID:   ent.ID,
+		Expiration: 1,
+		Consensus: node.ConsensusInfo{
+			ID: consensusSigner.Public(),
+		},
+	}
+	sigNode, err := node.MultiSignNode([]signature.Signer{nodeSigner}, registry.RegisterNodeSignatureContext, &nod)
+	require.NoError(err, "MultiSignNode")
+	err = state.SetNode(ctx, nil, &nod, sigNode)
+	require.NoError(err, "SetNode")
+	err = state.SetNodeStatus(ctx, nod.ID, &registry.NodeStatus{})
+	require.NoError(err, "SetNodeStatus")
+
+	// Find the last emitted registry event of the given kind.
+	lastEvent := func(ctx *abciAPI.Context, kind eventsAPI.TypedAttribute) {
+		var found bool
+		for _, ev := range ctx.GetEvents() {
+			if ev.Type != abciAPI.EventTypeForApp(app.Name()) {
+				continue
+			}
+			for _, attr := range ev.Attributes {
+				if !eventsAPI.IsAttributeKind(attr.Key, kind) {
+					continue
+				}
+				err = eventsAPI.DecodeValue(attr.Value, kind)
+				require.NoError(err, "DecodeValue")
+				found = true
+			}
+		}
+		require.True(found, "event %s should be emitted", kind.EventKind())
+	}
+
+	// Nodes that expire should be deregistered with the expired reason.
+	err = app.onRegistryEpochChanged(ctx, 3)
+	require.NoError(err, "onRegistryEpochChanged")
+
+	var nodeEv registry.NodeEvent
+	lastEvent(ctx, &nodeEv)
+	require.Equal(nod.ID, nodeEv.Node.ID, "expired node")
+	require.False(nodeEv.IsRegistration, "event is deregistration")
+	require.Equal(registry.DeregistrationReasonExpired, nodeEv.Reason, "deregistration reason")
+
+	// Entities that deregister should be deregistered with the voluntary reason.
+	txCtx := appState.NewContext(abciAPI.ContextDeliverTx)
+	defer txCtx.Close()
+	txCtx.SetTxSigner(ent.ID)
+	err = app.deregisterEntity(txCtx, state)
+	require.NoError(err, "deregisterEntity")
+
+	var entityEv registry.EntityEvent
+	lastEvent(txCtx, &entityEv)
+	require.Equal(ent.ID, entityEv.Entity.ID, "deregistered entity")
+	require.False(entityEv.IsRegistration, "event is deregistration")
+	require.Equal(registry.DeregistrationReasonVoluntary, entityEv.Reason, "deregistration reason")
+}
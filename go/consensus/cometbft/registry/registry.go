@@ -12,6 +12,7 @@ import (
 	cmttypes "github.com/cometbft/cometbft/types"
 	"github.com/eapache/channels"
 
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/common/entity"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
@@ -43,6 +44,8 @@ type serviceClient struct {
 	nodeListNotifier *pubsub.Broker
 	runtimeNotifier  *pubsub.Broker
 	eventNotifier    *pubsub.Broker
+
+	lastNodeListHash hash.Hash
 }
 
 // NodeListEpochInternalEvent is the per-epoch node list event.
@@ -107,6 +110,24 @@ func (sc *serviceClient) GetNodes(ctx context.Context, height int64) ([]*node.No
 	return q.Nodes(ctx)
 }
 
+func (sc *serviceClient) GetNodesForEntity(ctx context.Context, query *api.IDQuery) ([]*node.Node, error) {
+	q, err := sc.querier.QueryAt(ctx, query.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.NodesForEntity(ctx, query.ID)
+}
+
+func (sc *serviceClient) GetNodesPaged(ctx context.Context, query *api.GetNodesPagedQuery) (*api.NodesPage, error) {
+	q, err := sc.querier.QueryAt(ctx, query.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.NodesPaged(ctx, query.Offset, query.Limit)
+}
+
 func (sc *serviceClient) GetNodeByConsensusAddress(ctx context.Context, query *api.ConsensusAddressQuery) (*node.Node, error) {
 	q, err := sc.querier.QueryAt(ctx, query.Height)
 	if err != nil {
@@ -132,6 +153,15 @@ func (sc *serviceClient) WatchNodeList(context.Context) (<-chan *api.NodeList, p
 	return typedCh, sub, nil
 }
 
+func (sc *serviceClient) GetEpochNodeList(ctx context.Context, epoch beacon.EpochTime) (*api.NodeList, error) {
+	height, err := sc.backend.Beacon().GetEpochBlock(ctx, epoch)
+	if err != nil {
+		return nil, fmt.Errorf("registry: failed to get epoch block: %w", err)
+	}
+
+	return sc.getNodeList(ctx, height)
+}
+
 func (sc *serviceClient) GetRuntime(ctx context.Context, query *api.GetRuntimeQuery) (*api.Runtime, error) {
 	q, err := sc.querier.QueryAt(ctx, query.Height)
 	if err != nil {
@@ -259,6 +289,12 @@ func (sc *serviceClient) DeliverEvent(ctx context.Context, height int64, tx cmtt
 			)
 			continue
 		}
+		if !sc.nodeListChanged(nl) {
+			sc.logger.Debug("worker: node list unchanged, skipping broadcast",
+				"height", ev.Height,
+			)
+			continue
+		}
 		sc.nodeListNotifier.Broadcast(nl)
 	}
 
@@ -360,6 +396,16 @@ func EventsFromCometBFT(
 	return events, nodeListEvents, errs
 }
 
+// nodeListChanged returns true iff the given node list differs from the previously seen one.
+func (sc *serviceClient) nodeListChanged(nl *api.NodeList) bool {
+	h := hash.NewFrom(nl)
+	if h.Equal(&sc.lastNodeListHash) {
+		return false
+	}
+	sc.lastNodeListHash = h
+	return true
+}
+
 func (sc *serviceClient) getNodeList(ctx context.Context, height int64) (*api.NodeList, error) {
 	// Generate the nodelist.
 	q, err := sc.querier.QueryAt(ctx, height)
@@ -0,0 +1,129 @@
+package registry
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
+	tmapi "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/api"
+	app "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/registry"
+	registryState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/registry/state"
+	"github.com/oasisprotocol/oasis-core/go/registry/api"
+)
+
+func TestNodeListChanged(t *testing.T) {
+	require := require.New(t)
+
+	sc := &serviceClient{}
+
+	nodeA := &node.Node{ID: memorySigner.NewTestSigner("node list test node A").Public()}
+	nodeB := &node.Node{ID: memorySigner.NewTestSigner("node list test node B").Public()}
+
+	// The first node list should always be broadcast.
+	require.True(sc.nodeListChanged(&api.NodeList{Nodes: []*node.Node{nodeA}}))
+
+	// Consecutive epochs with identical membership should not be broadcast.
+	require.False(sc.nodeListChanged(&api.NodeList{Nodes: []*node.Node{nodeA}}))
+
+	// Membership changes should be broadcast.
+	require.True(sc.nodeListChanged(&api.NodeList{Nodes: []*node.Node{nodeA, nodeB}}))
+	require.True(sc.nodeListChanged(&api.NodeList{Nodes: []*node.Node{nodeB}}))
+	require.False(sc.nodeListChanged(&api.NodeList{Nodes: []*node.Node{nodeB}}))
+}
+
+func TestDeliverEventNodeList(t *testing.T) {
+	require := require.New(t)
+
+	appState := tmapi.NewMockApplicationState(&tmapi.MockApplicationStateConfig{
+		BlockHeight:  1000,
+		CurrentEpoch: 1,
+	})
+
+	sc := &serviceClient{
+		logger:           logging.GetLogger("cometbft/registry/test"),
+		querier:          app.NewQueryFactory(appState),
+		entityNotifier:   pubsub.NewBroker(false),
+		nodeNotifier:     pubsub.NewBroker(false),
+		nodeListNotifier: pubsub.NewBroker(false),
+		runtimeNotifier:  pubsub.NewBroker(false),
+		eventNotifier:    pubsub.NewBroker(false),
+	}
+
+	nodeListCh := make(chan *api.NodeList)
+	nodeListSub := sc.nodeListNotifier.Subscribe()
+	nodeListSub.Unwrap(nodeListCh)
+	defer nodeListSub.Close()
+
+	entityID := memorySigner.NewTestSigner("node list test entity").Public()
+	var nodes []*node.Node
+
+	// deliverEpoch delivers the node list event of an epoch transition, optionally registering
+	// a new node first.
+	deliverEpoch := func(epoch int, addNode bool) {
+		height := int64(1000 + epoch)
+		appState.UpdateMockApplicationStateConfig(&tmapi.MockApplicationStateConfig{
+			BlockHeight: height - 1,
+		})
+		ctx := appState.NewContext(tmapi.ContextEndBlock)
+		defer ctx.Close()
+
+		if addNode {
+			nodeSigner := memorySigner.NewTestSigner(fmt.Sprintf("node list test node %d", epoch))
+			consensusSigner := memorySigner.NewTestSigner(fmt.Sprintf("node list test node consensus %d", epoch))
+			nod := &node.Node{
+				Versioned:  cbor.NewVersioned(node.LatestNodeDescriptorVersion),
+				ID:         nodeSigner.Public(),
+				EntityID:   entityID,
+				Expiration: 100,
+				Consensus: node.ConsensusInfo{
+					ID: consensusSigner.Public(),
+				},
+			}
+			sigNode, err := node.MultiSignNode([]signature.Signer{nodeSigner}, api.RegisterNodeSignatureContext, nod)
+			require.NoError(err, "MultiSignNode")
+			err = registryState.NewMutableState(ctx.State()).SetNode(ctx, nil, nod, sigNode)
+			require.NoError(err, "SetNode")
+
+			nodes = append(nodes, nod)
+			api.SortNodeList(nodes)
+		}
+
+		ev := tmapi.NewEventBuilder(app.AppName).TypedAttribute(&api.NodeListEpochEvent{}).Event()
+		err := sc.DeliverEvent(ctx, height, nil, &ev)
+		require.NoError(err, "DeliverEvent")
+	}
+	requireBroadcast := func(msg string) {
+		select {
+		case nl := <-nodeListCh:
+			require.EqualValues(nodes, nl.Nodes, msg)
+		case <-time.After(time.Second):
+			t.Fatalf("failed to receive node list: %s", msg)
+		}
+	}
+	requireNoBroadcast := func(msg string) {
+		select {
+		case <-nodeListCh:
+			t.Fatalf("unexpected node list broadcast: %s", msg)
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+
+	deliverEpoch(1, true)
+	requireBroadcast("first epoch node list should be broadcast")
+
+	// Two epochs with identical membership should result in exactly one broadcast.
+	deliverEpoch(2, false)
+	requireNoBroadcast("unchanged node list should not be broadcast")
+
+	deliverEpoch(3, true)
+	requireBroadcast("changed node list should be broadcast")
+	requireNoBroadcast("each node list should only be broadcast once")
+}
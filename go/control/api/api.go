@@ -16,6 +16,7 @@ import (
 	p2p "github.com/oasisprotocol/oasis-core/go/p2p/api"
 	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
 	block "github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/commitment"
 	storage "github.com/oasisprotocol/oasis-core/go/storage/api"
 	upgrade "github.com/oasisprotocol/oasis-core/go/upgrade/api"
 	commonWorker "github.com/oasisprotocol/oasis-core/go/worker/common/api"
@@ -27,8 +28,14 @@ import (
 // ModuleName is the module name for the controller service.
 const ModuleName = "control"
 
-// ErrNotImplemented is the error raised when the node does not support the required functionality.
-var ErrNotImplemented = errors.New(ModuleName, 1, "control: not implemented")
+var (
+	// ErrNotImplemented is the error raised when the node does not support the required functionality.
+	ErrNotImplemented = errors.New(ModuleName, 1, "control: not implemented")
+
+	// ErrNoSuchRuntime is the error raised when the node does not run an executor for the
+	// requested runtime.
+	ErrNoSuchRuntime = errors.New(ModuleName, 2, "control: no such runtime")
+)
 
 // NodeController is a node controller interface.
 type NodeController interface {
@@ -60,6 +67,32 @@ type NodeController interface {
 
 	// GetStatus returns the current status overview of the node.
 	GetStatus(ctx context.Context) (*Status, error)
+
+	// AbortRuntimeBatch requests the executor of the given runtime to abort the batch that
+	// is currently being processed.
+	//
+	// This is useful when the runtime hangs while processing a batch but the node is
+	// otherwise healthy. The request is ignored if no batch is being processed.
+	AbortRuntimeBatch(ctx context.Context, runtimeID common.Namespace) error
+
+	// ReplayRuntimeBatch re-executes the batch with the given I/O root in the given historical
+	// runtime round using the currently active runtime version and returns the resulting compute
+	// results header.
+	//
+	// The batch is executed on a separate runtime instance and nothing is published, so this
+	// is safe to use on a node that is processing batches, e.g., to debug discrepancies.
+	ReplayRuntimeBatch(ctx context.Context, req *ReplayRuntimeBatchRequest) (*commitment.ComputeResultsHeader, error)
+}
+
+// ReplayRuntimeBatchRequest is a ReplayRuntimeBatch request.
+type ReplayRuntimeBatchRequest struct {
+	// RuntimeID is the identifier of the runtime.
+	RuntimeID common.Namespace `json:"runtime_id"`
+	// Round is the runtime round of the batch to replay.
+	Round uint64 `json:"round"`
+	// IORoot is the I/O root of the batch to replay, e.g., the one of the finalized block or of
+	// a discrepant commitment.
+	IORoot hash.Hash `json:"io_root"`
 }
 
 // Status is the current status overview.
@@ -5,7 +5,9 @@ import (
 
 	"google.golang.org/grpc"
 
+	"github.com/oasisprotocol/oasis-core/go/common"
 	cmnGrpc "github.com/oasisprotocol/oasis-core/go/common/grpc"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/commitment"
 	upgradeApi "github.com/oasisprotocol/oasis-core/go/upgrade/api"
 )
 
@@ -29,6 +31,10 @@ var (
 	methodCancelUpgrade = serviceName.NewMethod("CancelUpgrade", nil)
 	// methodGetStatus is the GetStatus method.
 	methodGetStatus = serviceName.NewMethod("GetStatus", nil)
+	// methodAbortRuntimeBatch is the AbortRuntimeBatch method.
+	methodAbortRuntimeBatch = serviceName.NewMethod("AbortRuntimeBatch", common.Namespace{})
+	// methodReplayRuntimeBatch is the ReplayRuntimeBatch method.
+	methodReplayRuntimeBatch = serviceName.NewMethod("ReplayRuntimeBatch", ReplayRuntimeBatchRequest{})
 
 	// serviceDesc is the gRPC service descriptor.
 	serviceDesc = grpc.ServiceDesc{
@@ -67,6 +73,14 @@ var (
 				MethodName: methodGetStatus.ShortName(),
 				Handler:    handlerGetStatus,
 			},
+			{
+				MethodName: methodAbortRuntimeBatch.ShortName(),
+				Handler:    handlerAbortRuntimeBatch,
+			},
+			{
+				MethodName: methodReplayRuntimeBatch.ShortName(),
+				Handler:    handlerReplayRuntimeBatch,
+			},
 		},
 		Streams: []grpc.StreamDesc{},
 	}
@@ -236,6 +250,52 @@ func handlerGetStatus(
 	return interceptor(ctx, nil, info, handler)
 }
 
+func handlerAbortRuntimeBatch(
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var runtimeID common.Namespace
+	if err := dec(&runtimeID); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return nil, srv.(NodeController).AbortRuntimeBatch(ctx, runtimeID)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodAbortRuntimeBatch.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, srv.(NodeController).AbortRuntimeBatch(ctx, req.(common.Namespace))
+	}
+	return interceptor(ctx, runtimeID, info, handler)
+}
+
+func handlerReplayRuntimeBatch(
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var req ReplayRuntimeBatchRequest
+	if err := dec(&req); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeController).ReplayRuntimeBatch(ctx, &req)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodReplayRuntimeBatch.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeController).ReplayRuntimeBatch(ctx, req.(*ReplayRuntimeBatchRequest))
+	}
+	return interceptor(ctx, &req, info, handler)
+}
+
 // RegisterService registers a new node controller service with the given gRPC server.
 func RegisterService(server *grpc.Server, service NodeController) {
 	server.RegisterService(&serviceDesc, service)
@@ -289,6 +349,18 @@ func (c *nodeControllerClient) GetStatus(ctx context.Context) (*Status, error) {
 	return &rsp, nil
 }
 
+func (c *nodeControllerClient) AbortRuntimeBatch(ctx context.Context, runtimeID common.Namespace) error {
+	return c.conn.Invoke(ctx, methodAbortRuntimeBatch.FullName(), runtimeID, nil)
+}
+
+func (c *nodeControllerClient) ReplayRuntimeBatch(ctx context.Context, req *ReplayRuntimeBatchRequest) (*commitment.ComputeResultsHeader, error) {
+	var rsp commitment.ComputeResultsHeader
+	if err := c.conn.Invoke(ctx, methodReplayRuntimeBatch.FullName(), req, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
 // NewNodeControllerClient creates a new gRPC node controller client service.
 func NewNodeControllerClient(c *grpc.ClientConn) NodeController {
 	return &nodeControllerClient{c}
@@ -40,6 +40,13 @@ var (
 		Run:   doDumpFixture,
 	}
 
+	diffFixturesCmd = &cobra.Command{
+		Use:   "diff-fixtures <fixture-a> <fixture-b>",
+		Short: "show differences between two fixture files",
+		Args:  cobra.ExactArgs(2),
+		Run:   doDiffFixtures,
+	}
+
 	rootFlags = flag.NewFlagSet("", flag.ContinueOnError)
 
 	cfgFile string
@@ -184,6 +191,20 @@ func doDumpFixture(*cobra.Command, []string) {
 	fmt.Printf("%s", data)
 }
 
+func doDiffFixtures(_ *cobra.Command, args []string) {
+	diffs, err := fixtures.DiffFixtureFiles(args[0], args[1])
+	if err != nil {
+		common.EarlyLogAndExit(fmt.Errorf("doDiffFixtures: failed to compare fixtures: %w", err))
+	}
+
+	for _, d := range diffs {
+		fmt.Println(d)
+	}
+	if len(diffs) > 0 {
+		os.Exit(1)
+	}
+}
+
 func init() {
 	common.SetBasicVersionTemplate(rootCmd)
 
@@ -203,6 +224,7 @@ func init() {
 
 	dumpFixtureCmd.Flags().AddFlagSet(fixtures.DefaultFixtureFlags)
 	rootCmd.AddCommand(dumpFixtureCmd)
+	rootCmd.AddCommand(diffFixturesCmd)
 
 	cobra.OnInitialize(func() {
 		if cfgFile != "" {
@@ -168,9 +168,9 @@ func newDefaultFixture() (*oasis.NetworkFixture, error) {
 			}
 		}
 		fixture.ComputeWorkers = []oasis.ComputeWorkerFixture{
-			{Entity: 1, Runtimes: []int{}, RuntimeProvisioner: runtimeProvisioner, RuntimeStatePaths: make(map[int]string)},
-			{Entity: 1, Runtimes: []int{}, RuntimeProvisioner: runtimeProvisioner, RuntimeStatePaths: make(map[int]string)},
-			{Entity: 1, Runtimes: []int{}, RuntimeProvisioner: runtimeProvisioner, RuntimeStatePaths: make(map[int]string)},
+			{Entity: 1, Runtimes: []int{}, RuntimeProvisioner: runtimeProvisioner, StorageBackend: database.BackendNameBadgerDB, RuntimeStatePaths: make(map[int]string)},
+			{Entity: 1, Runtimes: []int{}, RuntimeProvisioner: runtimeProvisioner, StorageBackend: database.BackendNameBadgerDB, RuntimeStatePaths: make(map[int]string)},
+			{Entity: 1, Runtimes: []int{}, RuntimeProvisioner: runtimeProvisioner, StorageBackend: database.BackendNameBadgerDB, RuntimeStatePaths: make(map[int]string)},
 		}
 
 		var runtimeIDs []common.Namespace
@@ -0,0 +1,131 @@
+package fixtures
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/oasisprotocol/oasis-core/go/oasis-test-runner/oasis"
+)
+
+// FieldDiff is a single difference between two fixtures.
+type FieldDiff struct {
+	// Path is the path of the differing field (e.g., `Validators[0].LogLevel`).
+	Path string
+	// A is the value of the field in the first fixture.
+	A interface{}
+	// B is the value of the field in the second fixture.
+	B interface{}
+}
+
+// String returns a string representation of the difference.
+func (d FieldDiff) String() string {
+	return fmt.Sprintf("%s: %+v != %+v", d.Path, d.A, d.B)
+}
+
+// DiffFixtures returns the list of fields that differ between the two fixtures.
+//
+// Structs, slices of equal length, maps and pointers are compared element by element, so that
+// the reported paths point to the innermost differing fields. Slices of different lengths are
+// reported as a whole, while map entries missing from one of the fixtures are reported
+// individually.
+func DiffFixtures(a, b *oasis.NetworkFixture) []FieldDiff {
+	var diffs []FieldDiff
+	diffValue(&diffs, "", reflect.ValueOf(a), reflect.ValueOf(b))
+	return diffs
+}
+
+// DiffFixtureFiles loads the two given JSON or YAML fixture files and returns the list of fields
+// that differ between them.
+func DiffFixtureFiles(pathA, pathB string) ([]FieldDiff, error) {
+	a, err := newFixtureFromFile(pathA)
+	if err != nil {
+		return nil, err
+	}
+	b, err := newFixtureFromFile(pathB)
+	if err != nil {
+		return nil, err
+	}
+	return DiffFixtures(a, b), nil
+}
+
+func diffValue(diffs *[]FieldDiff, path string, a, b reflect.Value) {
+	addDiff := func() {
+		*diffs = append(*diffs, FieldDiff{
+			Path: path,
+			A:    a.Interface(),
+			B:    b.Interface(),
+		})
+	}
+
+	switch a.Kind() {
+	case reflect.Ptr:
+		if a.IsNil() || b.IsNil() {
+			if a.IsNil() != b.IsNil() {
+				addDiff()
+			}
+			return
+		}
+		diffValue(diffs, path, a.Elem(), b.Elem())
+	case reflect.Struct:
+		t := a.Type()
+		for i := 0; i < t.NumField(); i++ {
+			if !t.Field(i).IsExported() {
+				// Structs with unexported fields are compared as a whole.
+				if !reflect.DeepEqual(a.Interface(), b.Interface()) {
+					addDiff()
+				}
+				return
+			}
+		}
+		for i := 0; i < t.NumField(); i++ {
+			diffValue(diffs, joinPath(path, t.Field(i).Name), a.Field(i), b.Field(i))
+		}
+	case reflect.Slice:
+		if a.Len() != b.Len() || a.IsNil() != b.IsNil() {
+			addDiff()
+			return
+		}
+		for i := 0; i < a.Len(); i++ {
+			diffValue(diffs, fmt.Sprintf("%s[%d]", path, i), a.Index(i), b.Index(i))
+		}
+	case reflect.Map:
+		if a.IsNil() != b.IsNil() {
+			addDiff()
+			return
+		}
+		keys := make(map[string]reflect.Value)
+		for _, k := range append(a.MapKeys(), b.MapKeys()...) {
+			keys[fmt.Sprintf("%v", k.Interface())] = k
+		}
+		names := make([]string, 0, len(keys))
+		for name := range keys {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			elemPath := fmt.Sprintf("%s[%s]", path, name)
+			av, bv := a.MapIndex(keys[name]), b.MapIndex(keys[name])
+			switch {
+			case !bv.IsValid():
+				*diffs = append(*diffs, FieldDiff{Path: elemPath, A: av.Interface()})
+			case !av.IsValid():
+				*diffs = append(*diffs, FieldDiff{Path: elemPath, B: bv.Interface()})
+			default:
+				diffValue(diffs, elemPath, av, bv)
+			}
+		}
+	default:
+		if !reflect.DeepEqual(a.Interface(), b.Interface()) {
+			addDiff()
+		}
+	}
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -4,23 +4,36 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
 
 	"github.com/oasisprotocol/oasis-core/go/oasis-test-runner/oasis"
 )
 
 const (
-	cfgFile = "fixture.file"
+	cfgFile         = "fixture.file"
+	cfgOverrideFile = "fixture.override_file"
 )
 
-// newFixtureFromFile parses given JSON file and creates new fixture object from it.
+// newFixtureFromFile parses given JSON or YAML file and creates new fixture object from it.
+//
+// Files with a .yaml or .yml extension are treated as YAML, all other files as JSON.
 func newFixtureFromFile(path string) (*oasis.NetworkFixture, error) {
 	f := oasis.NetworkFixture{}
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("newFixtureFromFile: failed to open fixture file: %w", err)
 	}
+
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml":
+		// The fixture only has JSON field tags, so convert YAML to JSON first.
+		if data, err = yamlToJSON(data); err != nil {
+			return nil, fmt.Errorf("newFixtureFromFile: failed to unmarshal YAML from fixture file: %w", err)
+		}
+	}
 	if err = json.Unmarshal(data, &f); err != nil {
 		return nil, fmt.Errorf("newFixtureFromFile: failed to unmarshal JSON from fixture file: %w", err)
 	}
@@ -28,7 +41,25 @@ func newFixtureFromFile(path string) (*oasis.NetworkFixture, error) {
 	return &f, nil
 }
 
+func yamlToJSON(data []byte) ([]byte, error) {
+	var v interface{}
+	if err := yaml.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+func jsonToYAML(data []byte) ([]byte, error) {
+	// JSON is a subset of YAML, so decoding it as YAML preserves all values.
+	var v interface{}
+	if err := yaml.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(v)
+}
+
 func init() {
-	FileFixtureFlags.String(cfgFile, "", "path to JSON-encoded fixture input file")
+	FileFixtureFlags.String(cfgFile, "", "path to JSON or YAML-encoded fixture input file")
+	FileFixtureFlags.String(cfgOverrideFile, "", "path to JSON or YAML-encoded fixture overrides applied on top of the fixture")
 	_ = viper.BindPFlags(FileFixtureFlags)
 }
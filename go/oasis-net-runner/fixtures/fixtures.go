@@ -29,11 +29,23 @@ func GetFixture() (f *oasis.NetworkFixture, err error) {
 		return
 	}
 
+	if viper.IsSet(cfgOverrideFile) {
+		var override *oasis.NetworkFixture
+		if override, err = newFixtureFromFile(viper.GetString(cfgOverrideFile)); err != nil {
+			return
+		}
+		f, err = MergeFixture(f, override)
+	}
+
 	return
 }
 
-// DumpFixture dumps given fixture to JSON-encoded bytes.
+// DumpFixture validates and dumps given fixture to JSON-encoded bytes.
 func DumpFixture(f *oasis.NetworkFixture) ([]byte, error) {
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+
 	data, err := json.MarshalIndent(f, "", "    ")
 	if err != nil {
 		return nil, err
@@ -41,3 +53,13 @@ func DumpFixture(f *oasis.NetworkFixture) ([]byte, error) {
 
 	return data, nil
 }
+
+// DumpFixtureYAML validates and dumps given fixture to YAML-encoded bytes.
+func DumpFixtureYAML(f *oasis.NetworkFixture) ([]byte, error) {
+	data, err := DumpFixture(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return jsonToYAML(data)
+}
@@ -3,11 +3,17 @@ package fixtures
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
 	consensusGenesis "github.com/oasisprotocol/oasis-core/go/consensus/genesis"
+	"github.com/oasisprotocol/oasis-core/go/oasis-test-runner/oasis"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	scheduler "github.com/oasisprotocol/oasis-core/go/scheduler/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/database"
 )
 
 func TestDefaultFixture(t *testing.T) {
@@ -20,6 +26,66 @@ func TestDefaultFixture(t *testing.T) {
 	require.NotNil(t, data)
 }
 
+func TestInvalidFixture(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		modify func(f *oasis.NetworkFixture)
+		errMsg string
+	}{
+		{
+			name:   "no node binary",
+			modify: func(f *oasis.NetworkFixture) { f.Network.NodeBinary = "" },
+			errMsg: "node binary not configured",
+		},
+		{
+			name:   "no validators",
+			modify: func(f *oasis.NetworkFixture) { f.Validators = nil },
+			errMsg: "at least one validator is required",
+		},
+		{
+			name:   "invalid log level",
+			modify: func(f *oasis.NetworkFixture) { f.Validators[0].LogLevel = "verbose" },
+			errMsg: "invalid log level",
+		},
+		{
+			name:   "negative timeout commit",
+			modify: func(f *oasis.NetworkFixture) { f.Network.Consensus.Parameters.TimeoutCommit = -time.Second },
+			errMsg: "timeout commit must be non-negative",
+		},
+		{
+			name:   "negative empty block interval",
+			modify: func(f *oasis.NetworkFixture) { f.Network.Consensus.Parameters.EmptyBlockInterval = -time.Second },
+			errMsg: "empty block interval must be non-negative",
+		},
+		{
+			name: "force committee role for unknown node",
+			modify: func(f *oasis.NetworkFixture) {
+				f.Runtimes[1].ForceCommitteeRoles = map[int]*scheduler.ForceElectCommitteeRole{
+					len(f.ComputeWorkers): {Kind: scheduler.KindComputeExecutor},
+				}
+			},
+			errMsg: "force committee role for unknown compute worker",
+		},
+		{
+			name: "managed extra argument",
+			modify: func(f *oasis.NetworkFixture) {
+				f.Validators[0].ExtraArgs = []oasis.Argument{{Name: "config", Values: []string{"/tmp/config.yml"}}}
+			},
+			errMsg: "extra argument 'config' is managed by the fixture",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := newDefaultFixture()
+			require.Nil(t, err)
+			tc.modify(f)
+
+			data, err := DumpFixture(f)
+			require.ErrorContains(t, err, tc.errMsg)
+			require.Nil(t, data)
+		})
+	}
+}
+
 func TestCustomFixture(t *testing.T) {
 	f, _ := newDefaultFixture()
 	f.Network.NodeBinary = "myNodeBinary"
@@ -27,6 +93,7 @@ func TestCustomFixture(t *testing.T) {
 	f.Network.Consensus.Parameters.GasCosts = transaction.Costs{
 		consensusGenesis.GasOpTxByte: 123456789,
 	}
+	f.Validators[0].StartAfter = []string{"seed-0"}
 
 	data, err := DumpFixture(f)
 	require.Nil(t, err)
@@ -39,3 +106,216 @@ func TestCustomFixture(t *testing.T) {
 	require.Nil(t, err)
 	require.EqualValues(t, f, fs)
 }
+
+func TestConsensusTimeoutFixture(t *testing.T) {
+	f, _ := newDefaultFixture()
+	f.Network.Consensus.Parameters.TimeoutCommit = 250 * time.Millisecond
+	f.Network.Consensus.Parameters.SkipTimeoutCommit = true
+	f.Network.Consensus.Parameters.EmptyBlockInterval = 5 * time.Second
+
+	data, err := DumpFixture(f)
+	require.Nil(t, err)
+	tmpFile, _ := os.CreateTemp("", "oasis-net-runner-timeoutfixture.*.json")
+	path := tmpFile.Name()
+	_, _ = tmpFile.Write(data)
+	tmpFile.Close()
+
+	fs, err := newFixtureFromFile(path)
+	require.Nil(t, err)
+	require.EqualValues(t, f, fs)
+	require.Equal(t, 250*time.Millisecond, fs.Network.Consensus.Parameters.TimeoutCommit)
+}
+
+func TestForceCommitteeRolesFixture(t *testing.T) {
+	f, _ := newDefaultFixture()
+	f.Runtimes[1].ForceCommitteeRoles = map[int]*scheduler.ForceElectCommitteeRole{
+		1: {
+			Kind:  scheduler.KindComputeExecutor,
+			Roles: []scheduler.Role{scheduler.RoleBackupWorker},
+		},
+	}
+
+	data, err := DumpFixture(f)
+	require.Nil(t, err)
+	tmpFile, _ := os.CreateTemp("", "oasis-net-runner-forceelectfixture.*.json")
+	path := tmpFile.Name()
+	_, _ = tmpFile.Write(data)
+	tmpFile.Close()
+
+	fs, err := newFixtureFromFile(path)
+	require.Nil(t, err)
+	require.EqualValues(t, f, fs)
+	require.True(t, fs.Runtimes[1].ForceCommitteeRoles[1].HasRole(scheduler.RoleBackupWorker))
+}
+
+func TestYAMLFixture(t *testing.T) {
+	f, _ := newDefaultFixture()
+	f.Network.Consensus.Parameters.GasCosts = transaction.Costs{
+		consensusGenesis.GasOpTxByte: 123456789,
+	}
+	f.Validators[0].StartAfter = []string{"seed-0"}
+
+	writeFixture := func(pattern string, data []byte) string {
+		tmpFile, _ := os.CreateTemp("", pattern)
+		path := tmpFile.Name()
+		_, _ = tmpFile.Write(data)
+		tmpFile.Close()
+		return path
+	}
+
+	jsonData, err := DumpFixture(f)
+	require.Nil(t, err)
+	yamlData, err := DumpFixtureYAML(f)
+	require.Nil(t, err)
+
+	fsJSON, err := newFixtureFromFile(writeFixture("oasis-net-runner-yamlfixture.*.json", jsonData))
+	require.Nil(t, err)
+	fsYAML, err := newFixtureFromFile(writeFixture("oasis-net-runner-yamlfixture.*.yaml", yamlData))
+	require.Nil(t, err)
+	require.EqualValues(t, fsJSON, fsYAML)
+	require.EqualValues(t, f, fsYAML)
+}
+
+func TestStorageBackendFixture(t *testing.T) {
+	f, _ := newDefaultFixture()
+	require.NotEmpty(t, f.ComputeWorkers, "default fixture should have compute workers")
+	for _, cw := range f.ComputeWorkers {
+		require.Equal(t, database.BackendNameBadgerDB, cw.StorageBackend, "default storage backend")
+	}
+	f.ComputeWorkers[0].StorageBackend = "myStorageBackend"
+
+	data, err := DumpFixture(f)
+	require.Nil(t, err)
+	tmpFile, _ := os.CreateTemp("", "oasis-net-runner-storagefixture.*.json")
+	path := tmpFile.Name()
+	_, _ = tmpFile.Write(data)
+	tmpFile.Close()
+
+	fs, err := newFixtureFromFile(path)
+	require.Nil(t, err)
+	require.EqualValues(t, f, fs)
+}
+
+func TestMergeFixture(t *testing.T) {
+	require := require.New(t)
+
+	base, err := newDefaultFixture()
+	require.NoError(err)
+
+	// Empty override should not change anything.
+	merged, err := MergeFixture(base, &oasis.NetworkFixture{})
+	require.NoError(err, "MergeFixture")
+	require.EqualValues(base, merged, "empty override should equal base")
+
+	// Scalar override.
+	override := &oasis.NetworkFixture{}
+	override.Network.NodeBinary = "myNodeBinary"
+	override.Network.Consensus.Parameters.TimeoutCommit = 42
+	merged, err = MergeFixture(base, override)
+	require.NoError(err, "MergeFixture")
+	require.Equal("myNodeBinary", merged.Network.NodeBinary, "node binary should be overridden")
+	require.EqualValues(42, merged.Network.Consensus.Parameters.TimeoutCommit, "timeout commit should be overridden")
+	require.Equal(base.Network.Consensus.Backend, merged.Network.Consensus.Backend, "other fields should be kept")
+	require.EqualValues(base.Validators, merged.Validators, "validators should be kept")
+	require.NotEqual("myNodeBinary", base.Network.NodeBinary, "base should not be modified")
+
+	// Slice replacement.
+	override = &oasis.NetworkFixture{
+		Validators: []oasis.ValidatorFixture{
+			{Entity: 1},
+		},
+	}
+	merged, err = MergeFixture(base, override)
+	require.NoError(err, "MergeFixture")
+	require.EqualValues(override.Validators, merged.Validators, "validators should be replaced")
+	require.EqualValues(base.ComputeWorkers, merged.ComputeWorkers, "compute workers should be kept")
+}
+
+func TestDiffFixtures(t *testing.T) {
+	require := require.New(t)
+
+	a, err := newDefaultFixture()
+	require.NoError(err)
+	b, err := newDefaultFixture()
+	require.NoError(err)
+	require.Empty(DiffFixtures(a, b), "identical fixtures should not differ")
+
+	b.Validators = append([]oasis.ValidatorFixture{}, a.Validators...)
+	b.Validators[0].LogLevel = "debug"
+	diffs := DiffFixtures(a, b)
+	require.Equal([]FieldDiff{
+		{
+			Path: "Validators[0].NodeFixture.LogLevel",
+			A:    a.Validators[0].LogLevel,
+			B:    "debug",
+		},
+	}, diffs, "diff should report the modified field")
+}
+
+func TestExtraArgsFixture(t *testing.T) {
+	f, _ := newDefaultFixture()
+	f.ComputeWorkers[0].ExtraArgs = []oasis.Argument{
+		{Name: "my.flag", Values: []string{"value"}},
+		{Name: "my.multi_flag", Values: []string{"a", "b"}, MultiValued: true},
+	}
+
+	data, err := DumpFixture(f)
+	require.Nil(t, err)
+	tmpFile, _ := os.CreateTemp("", "oasis-net-runner-extraargsfixture.*.json")
+	path := tmpFile.Name()
+	_, _ = tmpFile.Write(data)
+	tmpFile.Close()
+
+	fs, err := newFixtureFromFile(path)
+	require.Nil(t, err)
+	require.EqualValues(t, f, fs)
+	require.Equal(t, f.ComputeWorkers[0].ExtraArgs, fs.ComputeWorkers[0].ExtraArgs)
+}
+
+func TestLogLevelFixture(t *testing.T) {
+	f, _ := newDefaultFixture()
+	f.Validators[0].LogLevel = "info"
+	f.ComputeWorkers[0].LogLevel = "warn"
+
+	data, err := DumpFixture(f)
+	require.Nil(t, err)
+	tmpFile, _ := os.CreateTemp("", "oasis-net-runner-loglevelfixture.*.json")
+	path := tmpFile.Name()
+	_, _ = tmpFile.Write(data)
+	tmpFile.Close()
+
+	fs, err := newFixtureFromFile(path)
+	require.Nil(t, err)
+	require.EqualValues(t, f, fs)
+	require.Equal(t, "info", fs.Validators[0].LogLevel)
+	require.Equal(t, "warn", fs.ComputeWorkers[0].LogLevel)
+	require.Empty(t, fs.ComputeWorkers[1].LogLevel)
+}
+
+func TestRuntimeGenesisStateFixture(t *testing.T) {
+	f, _ := newDefaultFixture()
+
+	var stateRoot hash.Hash
+	stateRoot.FromBytes([]byte("genesis state root"))
+	f.Runtimes = append(f.Runtimes, oasis.RuntimeFixture{
+		Kind:             registry.KindCompute,
+		Entity:           0,
+		Keymanager:       -1,
+		GenesisRound:     42,
+		GenesisStateRoot: &stateRoot,
+		GovernanceModel:  registry.GovernanceEntity,
+	})
+	f.ComputeWorkers[0].RuntimeStatePaths[len(f.Runtimes)-1] = "/path/to/runtime/state"
+
+	data, err := DumpFixture(f)
+	require.Nil(t, err)
+	tmpFile, _ := os.CreateTemp("", "oasis-net-runner-genesisstatefixture.*.json")
+	path := tmpFile.Name()
+	_, _ = tmpFile.Write(data)
+	tmpFile.Close()
+
+	fs, err := newFixtureFromFile(path)
+	require.Nil(t, err)
+	require.EqualValues(t, f, fs)
+	require.EqualValues(t, &stateRoot, fs.Runtimes[len(fs.Runtimes)-1].GenesisStateRoot)
+}
@@ -0,0 +1,44 @@
+package fixtures
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/oasisprotocol/oasis-core/go/oasis-test-runner/oasis"
+)
+
+// MergeFixture returns a copy of the base fixture with all non-zero fields of
+// the override fixture applied on top of it.
+//
+// Nested structs are merged field by field, while slices (e.g., node lists),
+// maps and pointers are replaced as a whole and are shared with the inputs.
+// Since only non-zero fields are applied, an override cannot reset a field of
+// the base fixture back to its zero value.
+func MergeFixture(base, override *oasis.NetworkFixture) (*oasis.NetworkFixture, error) {
+	if base == nil {
+		return nil, fmt.Errorf("MergeFixture: base fixture not provided")
+	}
+
+	merged := *base
+	if override != nil {
+		mergeValue(reflect.ValueOf(&merged).Elem(), reflect.ValueOf(override).Elem())
+	}
+	return &merged, nil
+}
+
+func mergeValue(dst, src reflect.Value) {
+	if src.Kind() != reflect.Struct {
+		if !src.IsZero() {
+			dst.Set(src)
+		}
+		return
+	}
+
+	for i := 0; i < src.NumField(); i++ {
+		if !dst.Field(i).CanSet() {
+			// Skip unexported fields.
+			continue
+		}
+		mergeValue(dst.Field(i), src.Field(i))
+	}
+}
@@ -37,6 +37,9 @@ const (
 	MetricsModeNone = "none"
 	MetricsModePull = "pull"
 	MetricsModePush = "push"
+
+	// defaultPushTimeout is the timeout for pushing metrics to the push gateway.
+	defaultPushTimeout = 5 * time.Second
 )
 
 var (
@@ -143,6 +146,7 @@ type pushService struct {
 	jobName  string
 	labels   map[string]string
 	interval time.Duration
+	timeout  time.Duration
 
 	rsvc *resourceService
 
@@ -186,19 +190,26 @@ func (s *pushService) worker() {
 	for {
 		select {
 		case <-s.stopCh:
+			// Push the final metric values so that short-lived nodes do not
+			// lose any metrics updated since the last push.
+			s.push()
 			return
 		case <-t.C:
 		}
 
-		if err := s.pusher.Push(); err != nil {
-			s.Logger.Warn("Push: failed",
-				"err", err,
-			)
+		s.push()
+	}
+}
+
+func (s *pushService) push() {
+	if err := s.pusher.Push(); err != nil {
+		s.Logger.Warn("Push: failed",
+			"err", err,
+		)
 
-			// Once a pusher fails to push, it fails forever,
-			// so re-create the pusher.
-			s.initPusher(true)
-		}
+		// Once a pusher fails to push, it fails forever,
+		// so re-create the pusher.
+		s.initPusher(true)
 	}
 }
 
@@ -213,7 +224,9 @@ func (s *pushService) initPusher(isReinit bool) {
 		)
 	}
 
-	pusher := push.New(s.addr, s.jobName)
+	// Make sure that an unresponsive push gateway cannot block the service, in particular
+	// when pushing the final metric values on shutdown.
+	pusher := push.New(s.addr, s.jobName).Client(&http.Client{Timeout: s.timeout})
 	for k, v := range s.labels {
 		pusher = pusher.Grouping(k, v)
 	}
@@ -232,6 +245,7 @@ func newPushService() (service.BackgroundService, error) {
 		jobName:               config.GlobalConfig.Metrics.JobName,
 		labels:                config.GlobalConfig.Metrics.Labels,
 		interval:              config.GlobalConfig.Metrics.Interval,
+		timeout:               defaultPushTimeout,
 		rsvc:                  newResourceService(config.GlobalConfig.Metrics.Interval),
 		stopCh:                make(chan struct{}),
 		quitCh:                make(chan struct{}),
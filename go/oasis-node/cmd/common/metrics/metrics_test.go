@@ -1,9 +1,14 @@
 package metrics
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/config"
 )
 
 func TestEscapeLabelCharacters(t *testing.T) {
@@ -21,3 +26,87 @@ func TestEscapeLabelCharacters(t *testing.T) {
 		require.EqualValues(tc.expected, EscapeLabelCharacters(tc.input))
 	}
 }
+
+func TestPushTimeout(t *testing.T) {
+	require := require.New(t)
+
+	// The push gateway never responds.
+	releaseCh := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		<-releaseCh
+	}))
+	defer srv.Close()
+	defer close(releaseCh)
+
+	oldCfg := config.GlobalConfig.Metrics
+	defer func() {
+		config.GlobalConfig.Metrics = oldCfg
+	}()
+	config.GlobalConfig.Metrics.Mode = MetricsModePush
+	config.GlobalConfig.Metrics.Address = srv.URL
+	config.GlobalConfig.Metrics.JobName = "test"
+	config.GlobalConfig.Metrics.Labels = map[string]string{"instance": "test-instance"}
+	config.GlobalConfig.Metrics.Interval = time.Hour // Ensure no periodic pushes.
+
+	svc, err := newPushService()
+	require.NoError(err, "newPushService")
+	ps := svc.(*pushService)
+	ps.timeout = 100 * time.Millisecond
+	ps.initPusher(false)
+	require.NoError(svc.Start(), "Start")
+
+	// Stopping should not block on the final push.
+	svc.Stop()
+	select {
+	case <-svc.Quit():
+	case <-time.After(5 * time.Second):
+		t.Fatalf("failed to stop the push service")
+	}
+	svc.Cleanup()
+}
+
+func TestPushOnStop(t *testing.T) {
+	require := require.New(t)
+
+	pushCh := make(chan string, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pushCh <- r.Method + " " + r.URL.Path
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	oldCfg := config.GlobalConfig.Metrics
+	defer func() {
+		config.GlobalConfig.Metrics = oldCfg
+	}()
+	config.GlobalConfig.Metrics.Mode = MetricsModePush
+	config.GlobalConfig.Metrics.Address = srv.URL
+	config.GlobalConfig.Metrics.JobName = "test"
+	config.GlobalConfig.Metrics.Labels = map[string]string{"instance": "test-instance"}
+	config.GlobalConfig.Metrics.Interval = time.Hour // Ensure no periodic pushes.
+
+	svc, err := newPushService()
+	require.NoError(err, "newPushService")
+	require.NoError(svc.Start(), "Start")
+
+	select {
+	case <-pushCh:
+		t.Fatalf("metrics pushed before Stop")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	svc.Stop()
+	select {
+	case <-svc.Quit():
+	case <-time.After(5 * time.Second):
+		t.Fatalf("failed to stop the push service")
+	}
+	svc.Cleanup()
+
+	select {
+	case req := <-pushCh:
+		require.Equal("PUT /metrics/job/test/instance/test-instance", req, "final metrics should be pushed on Stop")
+	default:
+		t.Fatalf("metrics not pushed on Stop")
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/debug/byzantine"
 	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/debug/control"
 	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/debug/dumpdb"
+	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/debug/replay"
 	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/debug/storage"
 	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/debug/txsource"
 )
@@ -28,6 +29,7 @@ func Register(parentCmd *cobra.Command) {
 	dumpdb.Register(debugCmd)
 	beacon.Register(debugCmd)
 	bundle.Register(debugCmd)
+	replay.Register(debugCmd)
 
 	parentCmd.AddCommand(debugCmd)
 }
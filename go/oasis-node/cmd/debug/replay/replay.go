@@ -0,0 +1,85 @@
+// Package replay implements the replay-batch debug sub-command.
+package replay
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	control "github.com/oasisprotocol/oasis-core/go/control/api"
+	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
+	cmdGrpc "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/grpc"
+	cmdControl "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/control"
+)
+
+var (
+	replayBatchCmd = &cobra.Command{
+		Use:   "replay-batch <runtime-id> <round> <io-root>",
+		Short: "re-execute a historical runtime batch and show the resulting header",
+		Long: "Re-execute the batch with the given I/O root in the given runtime round on a " +
+			"separate instance of the currently active runtime version and show the resulting " +
+			"compute results header, which can be compared against the header of the finalized " +
+			"block or of a discrepant commitment.",
+		Args: cobra.ExactArgs(3),
+		Run:  doReplayBatch,
+	}
+
+	logger = logging.GetLogger("cmd/debug/replay")
+)
+
+func doReplayBatch(cmd *cobra.Command, args []string) {
+	var req control.ReplayRuntimeBatchRequest
+	if err := req.RuntimeID.UnmarshalText([]byte(args[0])); err != nil {
+		logger.Error("malformed runtime identifier",
+			"err", err,
+			"runtime_id", args[0],
+		)
+		os.Exit(1)
+	}
+	round, err := strconv.ParseUint(args[1], 10, 64)
+	if err != nil {
+		logger.Error("malformed round",
+			"err", err,
+			"round", args[1],
+		)
+		os.Exit(1)
+	}
+	req.Round = round
+	if err = req.IORoot.UnmarshalHex(args[2]); err != nil {
+		logger.Error("malformed I/O root",
+			"err", err,
+			"io_root", args[2],
+		)
+		os.Exit(1)
+	}
+
+	conn, client := cmdControl.DoConnect(cmd)
+	defer conn.Close()
+
+	header, err := client.ReplayRuntimeBatch(context.Background(), &req)
+	if err != nil {
+		logger.Error("failed to replay batch",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	prettyHeader, err := cmdCommon.PrettyJSONMarshal(header)
+	if err != nil {
+		logger.Error("failed to get pretty JSON of compute results header",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	fmt.Println(string(prettyHeader))
+}
+
+// Register registers the replay-batch sub-command.
+func Register(parentCmd *cobra.Command) {
+	replayBatchCmd.Flags().AddFlagSet(cmdGrpc.ClientFlags)
+
+	parentCmd.AddCommand(replayBatchCmd)
+}
@@ -103,8 +103,8 @@ const (
 
 	// CometBFT config flags.
 	CfgConsensusTimeoutCommit            = "consensus.cometbft.timeout_commit"
-	cfgConsensusSkipTimeoutCommit        = "consensus.cometbft.skip_timeout_commit"
-	cfgConsensusEmptyBlockInterval       = "consensus.cometbft.empty_block_interval"
+	CfgConsensusSkipTimeoutCommit        = "consensus.cometbft.skip_timeout_commit"
+	CfgConsensusEmptyBlockInterval       = "consensus.cometbft.empty_block_interval"
 	cfgConsensusMaxTxSizeBytes           = "consensus.cometbft.max_tx_size"
 	cfgConsensusMaxBlockSizeBytes        = "consensus.cometbft.max_block_size"
 	cfgConsensusMaxBlockGas              = "consensus.cometbft.max_block_gas"
@@ -287,8 +287,8 @@ func doInitGenesis(*cobra.Command, []string) {
 		Backend: viper.GetString(CfgConsensusBackend),
 		Parameters: consensusGenesis.Parameters{
 			TimeoutCommit:            viper.GetDuration(CfgConsensusTimeoutCommit),
-			SkipTimeoutCommit:        viper.GetBool(cfgConsensusSkipTimeoutCommit),
-			EmptyBlockInterval:       viper.GetDuration(cfgConsensusEmptyBlockInterval),
+			SkipTimeoutCommit:        viper.GetBool(CfgConsensusSkipTimeoutCommit),
+			EmptyBlockInterval:       viper.GetDuration(CfgConsensusEmptyBlockInterval),
 			MaxTxSize:                uint64(viper.GetSizeInBytes(cfgConsensusMaxTxSizeBytes)),
 			MaxBlockSize:             uint64(viper.GetSizeInBytes(cfgConsensusMaxBlockSizeBytes)),
 			MaxBlockGas:              transaction.Gas(viper.GetUint64(cfgConsensusMaxBlockGas)),
@@ -845,8 +845,8 @@ func init() {
 
 	// CometBFT config flags.
 	initGenesisFlags.Duration(CfgConsensusTimeoutCommit, 1*time.Second, "cometbft commit timeout")
-	initGenesisFlags.Bool(cfgConsensusSkipTimeoutCommit, false, "skip cometbft commit timeout")
-	initGenesisFlags.Duration(cfgConsensusEmptyBlockInterval, 0*time.Second, "cometbft empty block interval")
+	initGenesisFlags.Bool(CfgConsensusSkipTimeoutCommit, false, "skip cometbft commit timeout")
+	initGenesisFlags.Duration(CfgConsensusEmptyBlockInterval, 0*time.Second, "cometbft empty block interval")
 	initGenesisFlags.String(cfgConsensusMaxTxSizeBytes, "32kb", "cometbft maximum transaction size (in bytes)")
 	initGenesisFlags.String(cfgConsensusMaxBlockSizeBytes, "21mb", "cometbft maximum block size (in bytes)")
 	initGenesisFlags.Uint64(cfgConsensusMaxBlockGas, 0, "cometbft max gas used per block")
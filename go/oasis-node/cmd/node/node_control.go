@@ -13,6 +13,7 @@ import (
 	cmdFlags "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/flags"
 	p2p "github.com/oasisprotocol/oasis-core/go/p2p/api"
 	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/commitment"
 	storage "github.com/oasisprotocol/oasis-core/go/storage/api"
 	upgrade "github.com/oasisprotocol/oasis-core/go/upgrade/api"
 	keymanagerWorker "github.com/oasisprotocol/oasis-core/go/worker/keymanager/api"
@@ -113,6 +114,35 @@ func (n *Node) CancelUpgrade(_ context.Context, descriptor *upgrade.Descriptor)
 	return n.Upgrader.CancelUpgrade(descriptor)
 }
 
+// AbortRuntimeBatch implements control.NodeController.
+func (n *Node) AbortRuntimeBatch(_ context.Context, runtimeID common.Namespace) error {
+	execNode := n.ExecutorWorker.GetRuntime(runtimeID)
+	if execNode == nil {
+		return control.ErrNoSuchRuntime
+	}
+
+	n.logger.Warn("aborting runtime batch processing on operator request",
+		"runtime_id", runtimeID,
+	)
+
+	execNode.ForceAbort()
+	return nil
+}
+
+// ReplayRuntimeBatch implements control.NodeController.
+func (n *Node) ReplayRuntimeBatch(ctx context.Context, req *control.ReplayRuntimeBatchRequest) (*commitment.ComputeResultsHeader, error) {
+	execNode := n.ExecutorWorker.GetRuntime(req.RuntimeID)
+	if execNode == nil {
+		return nil, control.ErrNoSuchRuntime
+	}
+
+	batch, err := execNode.ReplayBatch(ctx, req.Round, req.IORoot)
+	if err != nil {
+		return nil, err
+	}
+	return &batch.Header, nil
+}
+
 // GetStatus implements control.NodeController.
 func (n *Node) GetStatus(ctx context.Context) (*control.Status, error) {
 	cs, err := n.getConsensusStatus(ctx)
@@ -3,9 +3,11 @@ package node
 import (
 	"context"
 
+	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/version"
 	"github.com/oasisprotocol/oasis-core/go/config"
 	control "github.com/oasisprotocol/oasis-core/go/control/api"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/commitment"
 	upgrade "github.com/oasisprotocol/oasis-core/go/upgrade/api"
 )
 
@@ -48,6 +50,16 @@ func (n *SeedNode) CancelUpgrade(context.Context, *upgrade.Descriptor) error {
 	return control.ErrNotImplemented
 }
 
+// AbortRuntimeBatch implements control.NodeController.
+func (n *SeedNode) AbortRuntimeBatch(context.Context, common.Namespace) error {
+	return control.ErrNotImplemented
+}
+
+// ReplayRuntimeBatch implements control.NodeController.
+func (n *SeedNode) ReplayRuntimeBatch(context.Context, *control.ReplayRuntimeBatchRequest) (*commitment.ComputeResultsHeader, error) {
+	return nil, control.ErrNotImplemented
+}
+
 // GetStatus implements control.NodeController.
 func (n *SeedNode) GetStatus(_ context.Context) (*control.Status, error) {
 	tmAddresses, err := n.cometbftSeed.GetAddresses()
@@ -26,6 +26,13 @@ type Argument struct {
 	MultiValued bool `json:"multi_valued"`
 }
 
+// managedArgs are the arguments that are always set by the network and must not be passed as
+// extra arguments.
+var managedArgs = map[string]bool{
+	cmdCommon.CfgConfigFile: true,
+	flags.CfgGenesisFile:    true,
+}
+
 type argBuilder struct {
 	vec []Argument
 
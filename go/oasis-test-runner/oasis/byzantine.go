@@ -6,8 +6,6 @@ import (
 	"strconv"
 
 	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
-	"github.com/oasisprotocol/oasis-core/go/common"
-	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/common/node"
 	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
 	scheduler "github.com/oasisprotocol/oasis-core/go/scheduler/api"
@@ -134,16 +132,7 @@ func (net *Network) NewByzantine(cfg *ByzantineCfg) (*Byzantine, error) {
 		rt := net.runtimes[cfg.Runtime].ID()
 		pk := host.nodeSigner
 
-		if net.cfg.SchedulerForceElect == nil {
-			net.cfg.SchedulerForceElect = make(map[common.Namespace]map[signature.PublicKey]*scheduler.ForceElectCommitteeRole)
-		}
-		if net.cfg.SchedulerForceElect[rt] == nil {
-			net.cfg.SchedulerForceElect[rt] = make(map[signature.PublicKey]*scheduler.ForceElectCommitteeRole)
-		}
-		if params := cfg.ForceElectParams; params != nil {
-			tmpParams := *params
-			net.cfg.SchedulerForceElect[rt][pk] = &tmpParams
-		}
+		net.forceElect(rt, pk, cfg.ForceElectParams)
 	}
 
 	return worker, nil
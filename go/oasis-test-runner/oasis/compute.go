@@ -217,8 +217,12 @@ func (net *Network) NewCompute(cfg *ComputeCfg) (*Compute, error) {
 	if cfg.RuntimeProvisioner == "" {
 		cfg.RuntimeProvisioner = runtimeConfig.RuntimeProvisionerSandboxed
 	}
-	if cfg.StorageBackend == "" {
+	switch cfg.StorageBackend {
+	case "":
 		cfg.StorageBackend = database.BackendNameBadgerDB
+	case database.BackendNameBadgerDB:
+	default:
+		return nil, fmt.Errorf("oasis/compute: unsupported storage backend: %s", cfg.StorageBackend)
 	}
 	// Initialize runtime state paths.
 	for i, path := range cfg.RuntimeStatePaths {
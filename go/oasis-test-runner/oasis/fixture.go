@@ -7,6 +7,7 @@ import (
 	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
 	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/common/node"
 	"github.com/oasisprotocol/oasis-core/go/common/sgx"
 	"github.com/oasisprotocol/oasis-core/go/oasis-test-runner/env"
@@ -33,6 +34,67 @@ type NetworkFixture struct {
 	ByzantineNodes     []ByzantineFixture        `json:"byzantine_nodes,omitempty"`
 }
 
+// Validate checks the fixture for basic consistency.
+func (f *NetworkFixture) Validate() error {
+	if f.Network.NodeBinary == "" {
+		return fmt.Errorf("fixture: node binary not configured")
+	}
+	if len(f.Validators) == 0 {
+		return fmt.Errorf("fixture: at least one validator is required")
+	}
+	if f.Network.Consensus.Parameters.TimeoutCommit < 0 {
+		return fmt.Errorf("fixture: consensus timeout commit must be non-negative")
+	}
+	if f.Network.Consensus.Parameters.EmptyBlockInterval < 0 {
+		return fmt.Errorf("fixture: consensus empty block interval must be non-negative")
+	}
+	for i, rt := range f.Runtimes {
+		for index := range rt.ForceCommitteeRoles {
+			if index < 0 || index >= len(f.ComputeWorkers) {
+				return fmt.Errorf("fixture: runtime %d: force committee role for unknown compute worker: %d", i, index)
+			}
+		}
+	}
+	for _, nf := range f.nodeFixtures() {
+		for _, arg := range nf.ExtraArgs {
+			if managedArgs[arg.Name] {
+				return fmt.Errorf("fixture: node '%s': extra argument '%s' is managed by the fixture", nf.Name, arg.Name)
+			}
+		}
+		if nf.LogLevel == "" {
+			continue
+		}
+		var level logging.Level
+		if err := level.Set(nf.LogLevel); err != nil {
+			return fmt.Errorf("fixture: node '%s': %w", nf.Name, err)
+		}
+	}
+	return nil
+}
+
+func (f *NetworkFixture) nodeFixtures() []*NodeFixture {
+	var nfs []*NodeFixture
+	for i := range f.Validators {
+		nfs = append(nfs, &f.Validators[i].NodeFixture)
+	}
+	for i := range f.Keymanagers {
+		nfs = append(nfs, &f.Keymanagers[i].NodeFixture)
+	}
+	for i := range f.ComputeWorkers {
+		nfs = append(nfs, &f.ComputeWorkers[i].NodeFixture)
+	}
+	for i := range f.Sentries {
+		nfs = append(nfs, &f.Sentries[i].NodeFixture)
+	}
+	for i := range f.Clients {
+		nfs = append(nfs, &f.Clients[i].NodeFixture)
+	}
+	for i := range f.ByzantineNodes {
+		nfs = append(nfs, &f.ByzantineNodes[i].NodeFixture)
+	}
+	return nfs
+}
+
 // Create instantiates the network described by the fixture.
 func (f *NetworkFixture) Create(env *env.Env) (*Network, error) {
 	// Use default MRSIGNER if not provided.
@@ -105,6 +167,21 @@ func (f *NetworkFixture) Create(env *env.Env) (*Network, error) {
 		}
 	}
 
+	// Rig committee elections.
+	for i, fx := range f.Runtimes {
+		var rt *Runtime
+		if rt, err = resolveRuntime(net, i); err != nil {
+			return nil, err
+		}
+		for index, params := range fx.ForceCommitteeRoles {
+			var workers []*Compute
+			if workers, err = resolveComputeWorkers(net, []int{index}); err != nil {
+				return nil, err
+			}
+			net.forceElect(rt.ID(), workers[0].NodeID, params)
+		}
+	}
+
 	// Provision the client nodes.
 	for _, fx := range f.Clients {
 		if _, err = fx.Create(net); err != nil {
@@ -151,8 +228,17 @@ type NodeFixture struct {
 	Name string `json:"node_name,omitempty"`
 
 	NoAutoStart bool `json:"no_auto_start,omitempty"`
+	// StartAfter is a list of node names that must be started and ready
+	// before this node is started. Leave empty to start the node together
+	// with all other nodes.
+	StartAfter []string `json:"start_after,omitempty"`
 
+	// ExtraArgs are additional arguments appended verbatim to the node command line.
 	ExtraArgs []Argument `json:"extra_args,omitempty"`
+
+	// LogLevel is the default log level of the node. Leave empty to use
+	// the network-wide node log level.
+	LogLevel string `json:"log_level,omitempty"`
 }
 
 // TEEFixture is a TEE configuration fixture.
@@ -201,11 +287,13 @@ func (f *ValidatorFixture) Create(net *Network) (*Validator, error) {
 			LogWatcherHandlerFactories:  f.LogWatcherHandlerFactories,
 			Consensus:                   f.Consensus,
 			NoAutoStart:                 f.NoAutoStart,
+			StartAfter:                  f.StartAfter,
 			CrashPointsProbability:      f.CrashPointsProbability,
 			SupplementarySanityInterval: f.Consensus.SupplementarySanityInterval,
 			EnableProfiling:             f.EnableProfiling,
 			Entity:                      entity,
 			ExtraArgs:                   f.ExtraArgs,
+			LogLevel:                    f.LogLevel,
 		},
 		Sentries: sentries,
 	})
@@ -236,6 +324,10 @@ type RuntimeFixture struct { // nolint: maligned
 
 	ExcludeFromGenesis bool `json:"exclude_from_genesis,omitempty"`
 	KeepBundles        bool `json:"keep_bundles,omitempty"`
+
+	// ForceCommitteeRoles are the committee roles that compute workers, identified by their index
+	// in the fixture, are force-elected into (UNSAFE, debug only).
+	ForceCommitteeRoles map[int]*scheduler.ForceElectCommitteeRole `json:"force_committee_roles,omitempty"`
 }
 
 // Create instantiates the runtime described by the fixture.
@@ -356,8 +448,10 @@ func (f *KeymanagerFixture) Create(net *Network) (*Keymanager, error) {
 			EnableProfiling:             f.EnableProfiling,
 			Consensus:                   f.Consensus,
 			NoAutoStart:                 f.NoAutoStart,
+			StartAfter:                  f.StartAfter,
 			Entity:                      entity,
 			ExtraArgs:                   f.ExtraArgs,
+			LogLevel:                    f.LogLevel,
 		},
 		RuntimeProvisioner: f.RuntimeProvisioner,
 		Runtime:            runtime,
@@ -417,6 +511,7 @@ func (f *ComputeWorkerFixture) Create(net *Network) (*Compute, error) {
 			AllowEarlyTermination:       f.AllowEarlyTermination,
 			AllowErrorTermination:       f.AllowErrorTermination,
 			NoAutoStart:                 f.NoAutoStart,
+			StartAfter:                  f.StartAfter,
 			CrashPointsProbability:      f.CrashPointsProbability,
 			SupplementarySanityInterval: f.Consensus.SupplementarySanityInterval,
 			EnableProfiling:             f.EnableProfiling,
@@ -424,6 +519,7 @@ func (f *ComputeWorkerFixture) Create(net *Network) (*Compute, error) {
 			Consensus:                   f.Consensus,
 			Entity:                      entity,
 			ExtraArgs:                   f.ExtraArgs,
+			LogLevel:                    f.LogLevel,
 		},
 		RuntimeProvisioner:      f.RuntimeProvisioner,
 		StorageBackend:          f.StorageBackend,
@@ -478,11 +574,13 @@ func (f *SentryFixture) Create(net *Network) (*Sentry, error) {
 		NodeCfg: NodeCfg{
 			Name:                        f.Name,
 			NoAutoStart:                 f.NoAutoStart,
+			StartAfter:                  f.StartAfter,
 			LogWatcherHandlerFactories:  f.LogWatcherHandlerFactories,
 			CrashPointsProbability:      f.CrashPointsProbability,
 			SupplementarySanityInterval: f.Consensus.SupplementarySanityInterval,
 			EnableProfiling:             f.EnableProfiling,
 			ExtraArgs:                   f.ExtraArgs,
+			LogLevel:                    f.LogLevel,
 		},
 		ValidatorIndices:  f.Validators,
 		ComputeIndices:    f.ComputeWorkers,
@@ -520,9 +618,11 @@ func (f *ClientFixture) Create(net *Network) (*Client, error) {
 			AllowErrorTermination:       f.AllowErrorTermination,
 			AllowEarlyTermination:       f.AllowEarlyTermination,
 			NoAutoStart:                 f.NoAutoStart,
+			StartAfter:                  f.StartAfter,
 			SupplementarySanityInterval: f.Consensus.SupplementarySanityInterval,
 			EnableProfiling:             f.EnableProfiling,
 			ExtraArgs:                   f.ExtraArgs,
+			LogLevel:                    f.LogLevel,
 		},
 		Runtimes:           f.Runtimes,
 		RuntimeProvisioner: f.RuntimeProvisioner,
@@ -569,7 +669,9 @@ func (f *ByzantineFixture) Create(net *Network) (*Byzantine, error) {
 			Consensus:                                f.Consensus,
 			EnableProfiling:                          f.EnableProfiling,
 			AllowEarlyTermination:                    true,
+			StartAfter:                               f.StartAfter,
 			Entity:                                   entity,
+			LogLevel:                                 f.LogLevel,
 		},
 		Script:           f.Script,
 		ExtraArgs:        f.ExtraArgs,
@@ -1,6 +1,7 @@
 package oasis
 
 import (
+	"context"
 	"crypto"
 	"crypto/x509"
 	"encoding/json"
@@ -397,6 +398,72 @@ func (net *Network) CheckLogWatchers() (err error) {
 	return
 }
 
+// nodeStartGroups splits the given nodes into groups which are started one
+// after another, so that each node is started after all of the nodes listed
+// in its StartAfter configuration. Nodes without dependencies all end up in
+// the first group and the relative node order is preserved within groups.
+func nodeStartGroups(nodes []*Node) ([][]*Node, error) {
+	known := make(map[string]bool, len(nodes))
+	for _, n := range nodes {
+		known[n.Name] = true
+	}
+	for _, n := range nodes {
+		for _, dep := range n.startAfter {
+			if !known[dep] {
+				return nil, fmt.Errorf("oasis: node %s must start after node %s which is not started automatically", n.Name, dep)
+			}
+		}
+	}
+
+	var groups [][]*Node
+	started := make(map[string]bool, len(nodes))
+	for len(nodes) > 0 {
+		var group, pending []*Node
+		for _, n := range nodes {
+			ready := true
+			for _, dep := range n.startAfter {
+				if !started[dep] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				group = append(group, n)
+			} else {
+				pending = append(pending, n)
+			}
+		}
+		if len(group) == 0 {
+			names := make([]string, 0, len(pending))
+			for _, n := range pending {
+				names = append(names, n.Name)
+			}
+			return nil, fmt.Errorf("oasis: cyclic start dependencies between nodes: %s", strings.Join(names, ", "))
+		}
+
+		for _, n := range group {
+			started[n.Name] = true
+		}
+		groups = append(groups, group)
+		nodes = pending
+	}
+
+	return groups, nil
+}
+
+// waitNodesReady waits for all of the given nodes to become ready.
+func waitNodesReady(nodes []*Node) error {
+	ctx, cancel := context.WithTimeout(context.Background(), nodeStartReadyTimeout)
+	defer cancel()
+
+	for _, n := range nodes {
+		if err := n.WaitReady(ctx); err != nil {
+			return fmt.Errorf("oasis: failed to wait for node %s to become ready: %w", n.Name, err)
+		}
+	}
+	return nil
+}
+
 // Start starts the network.
 func (net *Network) Start() error { // nolint: gocyclo
 	if net.running {
@@ -468,7 +535,7 @@ func (net *Network) Start() error { // nolint: gocyclo
 		iasNodeName = net.iasProxy.Name
 	}
 
-	net.logger.Debug("starting network nodes")
+	var nodes []*Node
 	for _, n := range net.nodes {
 		if n.Name == iasNodeName {
 			continue
@@ -477,24 +544,48 @@ func (net *Network) Start() error { // nolint: gocyclo
 			net.logger.Debug("skipping non-autostartable node", "name", n.Name)
 			continue
 		}
-		net.logger.Debug("starting node", "name", n.Name)
-		if err = n.Start(); err != nil {
-			net.logger.Error("failed to start node",
-				"name", n.Name,
-				"err", err,
-			)
-			return err
+		nodes = append(nodes, n)
+	}
+	groups, err := nodeStartGroups(nodes)
+	if err != nil {
+		net.logger.Error("failed to determine node start order",
+			"err", err,
+		)
+		return err
+	}
+
+	net.logger.Debug("starting network nodes")
+	for i, group := range groups {
+		for _, n := range group {
+			net.logger.Debug("starting node", "name", n.Name)
+			if err = n.Start(); err != nil {
+				net.logger.Error("failed to start node",
+					"name", n.Name,
+					"err", err,
+				)
+				return err
+			}
+
+			// HACK HACK HACK HACK HACK
+			//
+			// If you don't attempt to start the CometBFT Prometheus HTTP server
+			// (even if it is doomed to fail due to node already listening on the
+			// port), and you launch all the validators near simultaneously, there
+			// is a high chance that at least one of the validators will get upset
+			// and start refusing connections.
+			if n.hasValidators {
+				time.Sleep(validatorStartDelay)
+			}
 		}
 
-		// HACK HACK HACK HACK HACK
-		//
-		// If you don't attempt to start the CometBFT Prometheus HTTP server
-		// (even if it is doomed to fail due to node already listening on the
-		// port), and you launch all the validators near simultaneously, there
-		// is a high chance that at least one of the validators will get upset
-		// and start refusing connections.
-		if n.hasValidators {
-			time.Sleep(validatorStartDelay)
+		// Nodes in the following groups depend on this group being ready.
+		if i < len(groups)-1 {
+			if err = waitNodesReady(group); err != nil {
+				net.logger.Error("failed to wait for nodes to become ready",
+					"err", err,
+				)
+				return err
+			}
 		}
 	}
 
@@ -644,9 +735,12 @@ func (net *Network) startOasisNode(
 
 	cfg.Common.DataDir = node.DataDir()
 	cfg.Common.Log.Level = make(map[string]string)
-	if net.Config().NodeLogLevel != "" {
+	switch {
+	case node.logLevel != "":
+		cfg.Common.Log.Level["default"] = node.logLevel
+	case net.Config().NodeLogLevel != "":
 		cfg.Common.Log.Level["default"] = net.Config().NodeLogLevel
-	} else {
+	default:
 		cfg.Common.Log.Level["default"] = "debug"
 	}
 	if net.Config().NodeLogFormat != "" {
@@ -768,6 +862,21 @@ func (net *Network) startOasisNode(
 	return nil
 }
 
+// forceElect rigs the committee elections of the given runtime so that the given node is always
+// elected with the given parameters.
+func (net *Network) forceElect(rt common.Namespace, pk signature.PublicKey, params *scheduler.ForceElectCommitteeRole) {
+	if net.cfg.SchedulerForceElect == nil {
+		net.cfg.SchedulerForceElect = make(map[common.Namespace]map[signature.PublicKey]*scheduler.ForceElectCommitteeRole)
+	}
+	if net.cfg.SchedulerForceElect[rt] == nil {
+		net.cfg.SchedulerForceElect[rt] = make(map[signature.PublicKey]*scheduler.ForceElectCommitteeRole)
+	}
+	if params != nil {
+		tmpParams := *params
+		net.cfg.SchedulerForceElect[rt][pk] = &tmpParams
+	}
+}
+
 // MakeGenesis generates a new Genesis file.
 func (net *Network) MakeGenesis() error {
 	args := []string{
@@ -777,6 +886,7 @@ func (net *Network) MakeGenesis() error {
 		"--" + genesis.CfgInitialHeight, strconv.FormatInt(net.cfg.InitialHeight, 10),
 		"--" + genesis.CfgConsensusBackend, net.cfg.Consensus.Backend,
 		"--" + genesis.CfgConsensusTimeoutCommit, net.cfg.Consensus.Parameters.TimeoutCommit.String(),
+		"--" + genesis.CfgConsensusEmptyBlockInterval, net.cfg.Consensus.Parameters.EmptyBlockInterval.String(),
 		"--" + genesis.CfgRegistryEnableRuntimeGovernanceModels, "entity,runtime",
 		"--" + genesis.CfgRegistryDebugAllowUnroutableAddresses, "true",
 		"--" + genesis.CfgRegistryDebugAllowTestRuntimes, "true",
@@ -807,6 +917,9 @@ func (net *Network) MakeGenesis() error {
 	default:
 		return fmt.Errorf("oasis: unsupported beacon backend: %s", net.cfg.Beacon.Backend)
 	}
+	if net.cfg.Consensus.Parameters.SkipTimeoutCommit {
+		args = append(args, "--"+genesis.CfgConsensusSkipTimeoutCommit)
+	}
 	if net.cfg.Beacon.DebugMockBackend {
 		args = append(args, "--"+genesis.CfgBeaconDebugMockBackend)
 	}
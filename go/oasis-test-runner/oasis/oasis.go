@@ -28,7 +28,8 @@ import (
 const (
 	baseNodePort = 20000
 
-	validatorStartDelay = 3 * time.Second
+	validatorStartDelay   = 3 * time.Second
+	nodeStartReadyTimeout = 5 * time.Minute
 
 	defaultConsensusBackend          = "tendermint"
 	defaultEpochtimeCometBFTInterval = 30
@@ -100,10 +101,12 @@ type Node struct { // nolint: maligned
 	termErrorOk bool
 	isStopping  bool
 	noAutoStart bool
+	startAfter  []string
 
 	crashPointsProbability      float64
 	supplementarySanityInterval uint64
 
+	logLevel                                 string
 	disableDefaultLogWatcherHandlerFactories bool
 	logWatcherHandlerFactories               []log.WatcherHandlerFactory
 
@@ -463,7 +466,11 @@ type NodeCfg struct { // nolint: maligned
 	EnableProfiling             bool
 
 	NoAutoStart bool
+	StartAfter  []string
 
+	// LogLevel is the default log level of the node. Leave empty to use
+	// the network-wide node log level.
+	LogLevel                                 string
 	DisableDefaultLogWatcherHandlerFactories bool
 	LogWatcherHandlerFactories               []log.WatcherHandlerFactory
 
@@ -478,10 +485,12 @@ type NodeCfg struct { // nolint: maligned
 // Into sets node parameters of an existing node object from the configuration.
 func (cfg *NodeCfg) Into(node *Node) {
 	node.noAutoStart = cfg.NoAutoStart
+	node.startAfter = cfg.StartAfter
 	node.termEarlyOk = cfg.AllowEarlyTermination
 	node.termErrorOk = cfg.AllowErrorTermination
 	node.crashPointsProbability = cfg.CrashPointsProbability
 	node.supplementarySanityInterval = cfg.SupplementarySanityInterval
+	node.logLevel = cfg.LogLevel
 	node.disableDefaultLogWatcherHandlerFactories = cfg.DisableDefaultLogWatcherHandlerFactories
 	node.logWatcherHandlerFactories = cfg.LogWatcherHandlerFactories
 	node.consensus = cfg.Consensus
@@ -39,3 +39,67 @@ func TestNodeIdentity(t *testing.T) {
 	require.Equal(t, 1, bytes.Compare(b1, c0))
 	require.Equal(t, 1, bytes.Compare(c2, b1))
 }
+
+func TestNodeStartGroups(t *testing.T) {
+	require := require.New(t)
+
+	newNode := func(name string, startAfter ...string) *Node {
+		return &Node{Name: name, startAfter: startAfter}
+	}
+	groupNames := func(groups [][]*Node) [][]string {
+		var names [][]string
+		for _, group := range groups {
+			var g []string
+			for _, n := range group {
+				g = append(g, n.Name)
+			}
+			names = append(names, g)
+		}
+		return names
+	}
+
+	// Without dependencies all nodes are started together.
+	groups, err := nodeStartGroups([]*Node{
+		newNode("validator-0"),
+		newNode("validator-1"),
+		newNode("compute-0"),
+	})
+	require.NoError(err, "nodeStartGroups")
+	require.Equal([][]string{{"validator-0", "validator-1", "compute-0"}}, groupNames(groups))
+
+	// Dependencies split nodes into ordered groups.
+	groups, err = nodeStartGroups([]*Node{
+		newNode("client-0", "compute-0"),
+		newNode("validator-0"),
+		newNode("compute-0", "validator-0", "keymanager-0"),
+		newNode("keymanager-0", "validator-0"),
+		newNode("validator-1"),
+	})
+	require.NoError(err, "nodeStartGroups")
+	require.Equal([][]string{
+		{"validator-0", "validator-1"},
+		{"keymanager-0"},
+		{"compute-0"},
+		{"client-0"},
+	}, groupNames(groups))
+
+	// Cyclic dependencies should be rejected.
+	_, err = nodeStartGroups([]*Node{
+		newNode("validator-0"),
+		newNode("compute-0", "compute-1"),
+		newNode("compute-1", "client-0"),
+		newNode("client-0", "compute-0"),
+	})
+	require.Error(err, "nodeStartGroups should reject cyclic dependencies")
+
+	_, err = nodeStartGroups([]*Node{
+		newNode("validator-0", "validator-0"),
+	})
+	require.Error(err, "nodeStartGroups should reject self dependencies")
+
+	// Dependencies on nodes that are not started should be rejected.
+	_, err = nodeStartGroups([]*Node{
+		newNode("compute-0", "validator-0"),
+	})
+	require.Error(err, "nodeStartGroups should reject unknown dependencies")
+}
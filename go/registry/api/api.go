@@ -185,6 +185,13 @@ type Backend interface {
 	// GetNodes gets a list of all registered nodes.
 	GetNodes(context.Context, int64) ([]*node.Node, error)
 
+	// GetNodesForEntity gets a list of all registered nodes of the given entity.
+	GetNodesForEntity(context.Context, *IDQuery) ([]*node.Node, error)
+
+	// GetNodesPaged gets a bounded page of registered nodes, ordered by node
+	// identifier, together with the total number of registered nodes.
+	GetNodesPaged(context.Context, *GetNodesPagedQuery) (*NodesPage, error)
+
 	// GetNodeByConsensusAddress looks up a node by its consensus address at the
 	// specified block height. The nature and format of the consensus address depends
 	// on the specific consensus backend implementation used.
@@ -196,12 +203,19 @@ type Backend interface {
 
 	// WatchNodeList returns a channel that produces a stream of NodeList.
 	// Upon subscription, the node list for the current epoch will be sent
-	// immediately.
+	// immediately. Afterwards, a node list is only sent on epoch transitions
+	// where it differs from the previously sent one.
 	//
 	// Each node list will be sorted by node ID in lexicographically ascending
 	// order.
 	WatchNodeList(context.Context) (<-chan *NodeList, pubsub.ClosableSubscription, error)
 
+	// GetEpochNodeList returns the NodeList for the given epoch, as it was at the start of the
+	// epoch.
+	//
+	// The node list will be sorted by node ID in lexicographically ascending order.
+	GetEpochNodeList(context.Context, beacon.EpochTime) (*NodeList, error)
+
 	// GetRuntime gets a runtime by ID.
 	GetRuntime(context.Context, *GetRuntimeQuery) (*Runtime, error)
 
@@ -254,6 +268,21 @@ type GetRuntimesQuery struct {
 	IncludeSuspended bool  `json:"include_suspended"`
 }
 
+// GetNodesPagedQuery is a registry query for a page of nodes.
+type GetNodesPagedQuery struct {
+	Height int64  `json:"height"`
+	Offset uint64 `json:"offset"`
+	Limit  uint64 `json:"limit"`
+}
+
+// NodesPage is a page of registered nodes.
+type NodesPage struct {
+	// Nodes are the nodes in the requested page.
+	Nodes []*node.Node `json:"nodes"`
+	// Total is the total number of registered nodes.
+	Total uint64 `json:"total"`
+}
+
 // ConsensusAddressQuery is a registry query by consensus address.
 // The nature and format of the consensus address depends on the specific
 // consensus backend implementation used.
@@ -295,11 +324,36 @@ func NewProveFreshnessTx(nonce uint64, fee *transaction.Fee, blob [32]byte) *tra
 	return transaction.NewTransaction(nonce, fee, MethodProveFreshness, blob)
 }
 
+// DeregistrationReason is the reason for an entity or node deregistration.
+//
+// All reasons are defined so that the set of values is stable, but only the
+// voluntary and expired reasons are currently emitted. An entity can only be
+// deregistered once it has no registered nodes, so deregistering it never
+// cascades to its nodes, and slashing freezes nodes instead of removing them.
+type DeregistrationReason string
+
+const (
+	// DeregistrationReasonVoluntary is the reason for deregistrations that were
+	// requested by the owner (e.g., via a DeregisterEntity transaction).
+	DeregistrationReasonVoluntary DeregistrationReason = "voluntary"
+	// DeregistrationReasonExpired is the reason for deregistrations caused by the
+	// descriptor expiring.
+	DeregistrationReasonExpired DeregistrationReason = "expired"
+	// DeregistrationReasonEntityDeregistered is the reason for node deregistrations
+	// caused by the owning entity being deregistered.
+	DeregistrationReasonEntityDeregistered DeregistrationReason = "entity-deregistered"
+	// DeregistrationReasonSlashed is the reason for deregistrations caused by the
+	// node being slashed.
+	DeregistrationReasonSlashed DeregistrationReason = "slashed"
+)
+
 // EntityEvent is the event that is returned via WatchEntities to signify
 // entity registration changes and updates.
 type EntityEvent struct {
 	Entity         *entity.Entity `json:"entity"`
 	IsRegistration bool           `json:"is_registration"`
+	// Reason is the deregistration reason and is only set for deregistrations.
+	Reason DeregistrationReason `json:"reason,omitempty"`
 }
 
 // EventKind returns a string representation of this event's kind.
@@ -312,6 +366,8 @@ func (e *EntityEvent) EventKind() string {
 type NodeEvent struct {
 	Node           *node.Node `json:"node"`
 	IsRegistration bool       `json:"is_registration"`
+	// Reason is the deregistration reason and is only set for deregistrations.
+	Reason DeregistrationReason `json:"reason,omitempty"`
 }
 
 // EventKind returns a string representation of this event's kind.
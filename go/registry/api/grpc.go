@@ -5,6 +5,7 @@ import (
 
 	"google.golang.org/grpc"
 
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
 	"github.com/oasisprotocol/oasis-core/go/common/entity"
 	cmnGrpc "github.com/oasisprotocol/oasis-core/go/common/grpc"
 	"github.com/oasisprotocol/oasis-core/go/common/node"
@@ -27,6 +28,12 @@ var (
 	methodGetNodeStatus = serviceName.NewMethod("GetNodeStatus", IDQuery{})
 	// methodGetNodes is the GetNodes method.
 	methodGetNodes = serviceName.NewMethod("GetNodes", int64(0))
+	// methodGetNodesForEntity is the GetNodesForEntity method.
+	methodGetNodesForEntity = serviceName.NewMethod("GetNodesForEntity", IDQuery{})
+	// methodGetNodesPaged is the GetNodesPaged method.
+	methodGetNodesPaged = serviceName.NewMethod("GetNodesPaged", GetNodesPagedQuery{})
+	// methodGetEpochNodeList is the GetEpochNodeList method.
+	methodGetEpochNodeList = serviceName.NewMethod("GetEpochNodeList", beacon.EpochTime(0))
 	// methodGetRuntime is the GetRuntime method.
 	methodGetRuntime = serviceName.NewMethod("GetRuntime", GetRuntimeQuery{})
 	// methodGetRuntimes is the GetRuntimes method.
@@ -78,6 +85,18 @@ var (
 				MethodName: methodGetNodes.ShortName(),
 				Handler:    handlerGetNodes,
 			},
+			{
+				MethodName: methodGetNodesForEntity.ShortName(),
+				Handler:    handlerGetNodesForEntity,
+			},
+			{
+				MethodName: methodGetNodesPaged.ShortName(),
+				Handler:    handlerGetNodesPaged,
+			},
+			{
+				MethodName: methodGetEpochNodeList.ShortName(),
+				Handler:    handlerGetEpochNodeList,
+			},
 			{
 				MethodName: methodGetRuntime.ShortName(),
 				Handler:    handlerGetRuntime,
@@ -267,6 +286,75 @@ func handlerGetNodes(
 	return interceptor(ctx, height, info, handler)
 }
 
+func handlerGetNodesForEntity(
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var query IDQuery
+	if err := dec(&query); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Backend).GetNodesForEntity(ctx, &query)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetNodesForEntity.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Backend).GetNodesForEntity(ctx, req.(*IDQuery))
+	}
+	return interceptor(ctx, &query, info, handler)
+}
+
+func handlerGetEpochNodeList(
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var epoch beacon.EpochTime
+	if err := dec(&epoch); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Backend).GetEpochNodeList(ctx, epoch)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetEpochNodeList.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Backend).GetEpochNodeList(ctx, req.(beacon.EpochTime))
+	}
+	return interceptor(ctx, epoch, info, handler)
+}
+
+func handlerGetNodesPaged(
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var query GetNodesPagedQuery
+	if err := dec(&query); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Backend).GetNodesPaged(ctx, &query)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetNodesPaged.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Backend).GetNodesPaged(ctx, req.(*GetNodesPagedQuery))
+	}
+	return interceptor(ctx, &query, info, handler)
+}
+
 func handlerGetRuntime(
 	srv interface{},
 	ctx context.Context,
@@ -613,6 +701,30 @@ func (c *registryClient) GetNodes(ctx context.Context, height int64) ([]*node.No
 	return rsp, nil
 }
 
+func (c *registryClient) GetNodesForEntity(ctx context.Context, query *IDQuery) ([]*node.Node, error) {
+	var rsp []*node.Node
+	if err := c.conn.Invoke(ctx, methodGetNodesForEntity.FullName(), query, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (c *registryClient) GetEpochNodeList(ctx context.Context, epoch beacon.EpochTime) (*NodeList, error) {
+	var rsp NodeList
+	if err := c.conn.Invoke(ctx, methodGetEpochNodeList.FullName(), epoch, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
+func (c *registryClient) GetNodesPaged(ctx context.Context, query *GetNodesPagedQuery) (*NodesPage, error) {
+	var rsp NodesPage
+	if err := c.conn.Invoke(ctx, methodGetNodesPaged.FullName(), query, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
 func (c *registryClient) WatchNodes(ctx context.Context) (<-chan *NodeEvent, pubsub.ClosableSubscription, error) {
 	ctx, sub := pubsub.NewContextSubscription(ctx)
 
@@ -404,12 +404,38 @@ func testRegistryEntityNodes( // nolint: gocyclo
 	t.Run("NodeList", func(t *testing.T) {
 		require := require.New(t)
 
+		nodeListCh, nodeListSub, err := backend.WatchNodeList(ctx)
+		require.NoError(err, "WatchNodeList")
+		defer nodeListSub.Close()
+
+		// Drain the node list for the current epoch.
+		select {
+		case <-nodeListCh:
+		case <-time.After(recvTimeout):
+			t.Fatalf("failed to receive current node list")
+		}
+
 		expectedNodeList := getExpectedNodeList()
 		epoch = beaconTests.MustAdvanceEpoch(t, timeSource)
 
+		// The membership changed, so the node list for the epoch should be broadcast.
+		var nodeList *api.NodeList
+		select {
+		case nodeList = <-nodeListCh:
+		case <-time.After(recvTimeout):
+			t.Fatalf("failed to receive node list")
+		}
+		epochNodeList, err := backend.GetEpochNodeList(ctx, epoch)
+		require.NoError(err, "GetEpochNodeList")
+		require.EqualValues(nodeList, epochNodeList, "epoch node list")
+
 		registeredNodes, nerr := backend.GetNodes(ctx, consensusAPI.HeightLatest)
 		require.NoError(nerr, "GetNodes")
 
+		// The node list for the current epoch should match the registered nodes.
+		api.SortNodeList(registeredNodes)
+		require.EqualValues(registeredNodes, epochNodeList.Nodes, "epoch node list")
+
 		// Remove the pre-exiting validator node.
 		for i, nd := range registeredNodes {
 			if nd.EntityID.Equal(validatorEntityID) {
@@ -420,6 +446,52 @@ func testRegistryEntityNodes( // nolint: gocyclo
 		require.EqualValues(expectedNodeList, registeredNodes, "node list")
 	})
 
+	t.Run("NodesForEntity", func(t *testing.T) {
+		require := require.New(t)
+
+		expectedNodeList := getExpectedNodeList()
+		for _, te := range entities {
+			expectedNodes := []*node.Node{}
+			for _, nd := range expectedNodeList {
+				if nd.EntityID.Equal(te.Entity.ID) {
+					expectedNodes = append(expectedNodes, nd)
+				}
+			}
+
+			entityNodes, nerr := backend.GetNodesForEntity(ctx, &api.IDQuery{ID: te.Entity.ID, Height: consensusAPI.HeightLatest})
+			require.NoError(nerr, "GetNodesForEntity")
+			api.SortNodeList(entityNodes)
+			require.EqualValues(expectedNodes, entityNodes, "nodes for entity %s", te.Entity.ID)
+		}
+
+		// Unknown entities should have no nodes.
+		unknownEntity := memorySigner.NewTestSigner("registry test unknown entity")
+		entityNodes, nerr := backend.GetNodesForEntity(ctx, &api.IDQuery{ID: unknownEntity.Public(), Height: consensusAPI.HeightLatest})
+		require.NoError(nerr, "GetNodesForEntity")
+		require.Empty(entityNodes, "nodes for unknown entity")
+	})
+
+	t.Run("NodesPaged", func(t *testing.T) {
+		require := require.New(t)
+
+		registeredNodes, nerr := backend.GetNodes(ctx, consensusAPI.HeightLatest)
+		require.NoError(nerr, "GetNodes")
+		api.SortNodeList(registeredNodes)
+
+		var pagedNodes []*node.Node
+		for offset := uint64(0); ; offset += 2 {
+			page, nerr := backend.GetNodesPaged(ctx, &api.GetNodesPagedQuery{Height: consensusAPI.HeightLatest, Offset: offset, Limit: 2})
+			require.NoError(nerr, "GetNodesPaged")
+			require.EqualValues(len(registeredNodes), page.Total, "total number of nodes")
+			if len(page.Nodes) == 0 {
+				break
+			}
+			require.LessOrEqual(len(page.Nodes), 2, "page should be bounded by the limit")
+			pagedNodes = append(pagedNodes, page.Nodes...)
+		}
+		require.EqualValues(registeredNodes, pagedNodes, "paged node list")
+	})
+
 	t.Run("NodeUnfreeze", func(t *testing.T) {
 		require := require.New(t)
 
@@ -481,6 +553,7 @@ func testRegistryEntityNodes( // nolint: gocyclo
 
 				deregEvents++
 				require.False(ev.IsRegistration, "event is deregistration")
+				require.Equal(api.DeregistrationReasonExpired, ev.Reason, "deregistration reason")
 				deregisteredNodes[ev.Node.ID] = ev.Node
 
 				// Make sure that GetEvents also returns the deregistration event.
@@ -567,6 +640,7 @@ func testRegistryEntityNodes( // nolint: gocyclo
 		case ev := <-entityCh:
 			require.EqualValues(entities[0].Entity, ev.Entity, "deregistered entity")
 			require.False(ev.IsRegistration, "event is deregistration")
+			require.Equal(api.DeregistrationReasonVoluntary, ev.Reason, "deregistration reason")
 
 			// Make sure that GetEvents also returns the deregistration event.
 			evts, err := backend.GetEvents(ctx, consensusAPI.HeightLatest)
@@ -614,6 +688,7 @@ func testRegistryEntityNodes( // nolint: gocyclo
 			case ev := <-entityCh:
 				require.EqualValues(v.Entity, ev.Entity, "deregistered entity")
 				require.False(ev.IsRegistration, "event is deregistration")
+				require.Equal(api.DeregistrationReasonVoluntary, ev.Reason, "deregistration reason")
 
 				// Make sure that GetEvents also returns the deregistration event.
 				evts, err := backend.GetEvents(ctx, consensusAPI.HeightLatest)
@@ -666,6 +741,7 @@ func testRegistryEntityNodes( // nolint: gocyclo
 
 				deregEvents++
 				require.False(ev.IsRegistration, "event is deregistration")
+				require.Equal(api.DeregistrationReasonExpired, ev.Reason, "deregistration reason")
 				deregisteredNodes[ev.Node.ID] = ev.Node
 			case <-time.After(recvTimeout):
 				t.Fatalf("failed to receive node deregistration event")
@@ -1772,6 +1848,7 @@ func (rt *TestRuntime) Cleanup(t *testing.T, backend api.Backend, consensus cons
 	case ev := <-entityCh:
 		require.EqualValues(rt.entity.Entity, ev.Entity, "deregistered entity")
 		require.False(ev.IsRegistration, "event is deregistration")
+		require.Equal(api.DeregistrationReasonVoluntary, ev.Reason, "deregistration reason")
 	case <-time.After(recvTimeout):
 		t.Fatalf("failed to receive entity deregistration event")
 	}
@@ -1792,6 +1869,7 @@ func (rt *TestRuntime) Cleanup(t *testing.T, backend api.Backend, consensus cons
 				continue
 			}
 			require.False(ev.IsRegistration, "event is deregistration")
+			require.Equal(api.DeregistrationReasonExpired, ev.Reason, "deregistration reason")
 			numDereg++
 		case <-time.After(recvTimeout):
 			t.Fatalf("failed to receive node deregistration event")
@@ -2,7 +2,13 @@
 package host
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"io"
+	"slices"
+	"sync"
+	"time"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/node"
@@ -12,6 +18,16 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/runtime/host/protocol"
 )
 
+var (
+	// ErrNoProcess is the error returned when a runtime is not hosted in a process.
+	ErrNoProcess = errors.New("runtime is not hosted in a process")
+
+	// ErrPermanentInitFailure is the error that host initializers can wrap to signal that the
+	// runtime cannot be started and that further start attempts should not be made (e.g., due
+	// to an attestation policy violation).
+	ErrPermanentInitFailure = errors.New("permanent runtime initialization failure")
+)
+
 // Config contains common configuration for the provisioned runtime.
 type Config struct {
 	// Bundle is the runtime bundle.
@@ -25,6 +41,17 @@ type Config struct {
 
 	// LocalConfig is the node-local runtime configuration.
 	LocalConfig map[string]interface{}
+
+	// ExtraEnv are additional environment variables passed to the runtime process. Sandboxed
+	// provisioners that run the runtime via a loader (e.g., SGX) pass them to the loader process.
+	// Variables configured by the provisioner itself cannot be overridden.
+	ExtraEnv map[string]string
+
+	// BindRO are additional read-only bind mounts into the runtime sandbox, mapping host paths
+	// to paths inside the sandbox. They are applied by all sandboxed provisioners, including ones
+	// that run the runtime via a loader (e.g., SGX). Provisioners may restrict which host paths
+	// can be bound and mounts configured by the provisioner itself cannot be overridden.
+	BindRO map[string]string
 }
 
 // RuntimeBundle is a exploded runtime bundle ready for execution.
@@ -33,6 +60,11 @@ type RuntimeBundle struct {
 
 	// Exeuctable is the path to the extracted ELF or TEE executable.
 	Path string
+
+	// Binary is an optional reader providing the ELF executable. In case it is specified, the
+	// executable is materialized on each start and Path is ignored by provisioners that support
+	// it. The reader is fully consumed when the runtime is provisioned.
+	Binary io.Reader
 }
 
 // Provisioner is the runtime provisioner interface.
@@ -62,6 +94,9 @@ type Runtime interface {
 	// runtime is not running inside a TEE.
 	GetCapabilityTEE() (*node.CapabilityTEE, error)
 
+	// GetProcessInfo retrieves information about the process currently hosting the runtime.
+	GetProcessInfo() (*ProcessInfo, error)
+
 	// Call sends a request message to the runtime over the Runtime Host Protocol and waits for the
 	// response (which may be a failure).
 	Call(ctx context.Context, body *protocol.Body) (*protocol.Body, error)
@@ -72,6 +107,9 @@ type Runtime interface {
 	// WatchEvents subscribes to runtime status events.
 	WatchEvents() (<-chan *Event, pubsub.ClosableSubscription)
 
+	// WatchEventsFiltered subscribes to runtime status events of the given kinds only.
+	WatchEventsFiltered(kinds ...EventKind) (<-chan *Event, pubsub.ClosableSubscription)
+
 	// Start starts the runtime.
 	Start()
 
@@ -79,10 +117,26 @@ type Runtime interface {
 	// In case abort fails or force flag is set, the runtime will be restarted.
 	Abort(ctx context.Context, force bool) error
 
+	// Restart cleanly stops the runtime and starts it again, blocking until the runtime has been
+	// started or the context is canceled.
+	Restart(ctx context.Context) error
+
 	// Stop signals the provisioned runtime to stop.
 	Stop()
 }
 
+// ProcessInfo contains information about the process hosting a runtime.
+type ProcessInfo struct {
+	// PID is the operating system process identifier.
+	PID int
+
+	// StartTime is the time when the process was started.
+	StartTime time.Time
+
+	// RestartCount is the number of times the runtime has been restarted.
+	RestartCount uint64
+}
+
 // RuntimeEventEmitter is the interface for emitting events for a provisioned runtime.
 type RuntimeEventEmitter interface {
 	// EmitEvent allows the caller to emit a runtime event.
@@ -91,11 +145,128 @@ type RuntimeEventEmitter interface {
 
 // Event is a runtime host event.
 type Event struct {
-	Started       *StartedEvent
-	FailedToStart *FailedToStartEvent
-	Stopped       *StoppedEvent
-	Updated       *UpdatedEvent
-	ConfigUpdated *ConfigUpdatedEvent
+	Started         *StartedEvent
+	FailedToStart   *FailedToStartEvent
+	Stopped         *StoppedEvent
+	Updated         *UpdatedEvent
+	ConfigUpdated   *ConfigUpdatedEvent
+	VersionMismatch *VersionMismatchEvent
+}
+
+// EventKind is the kind of a runtime host event.
+type EventKind uint8
+
+const (
+	// EventKindUnknown is an unknown or empty event.
+	EventKindUnknown EventKind = iota
+	// EventKindStarted is the kind of runtime started events.
+	EventKindStarted
+	// EventKindFailedToStart is the kind of runtime failed to start events.
+	EventKindFailedToStart
+	// EventKindStopped is the kind of runtime stopped events.
+	EventKindStopped
+	// EventKindUpdated is the kind of runtime metadata updated events.
+	EventKindUpdated
+	// EventKindConfigUpdated is the kind of runtime configuration updated events.
+	EventKindConfigUpdated
+	// EventKindVersionMismatch is the kind of runtime version mismatch events.
+	EventKindVersionMismatch
+)
+
+// String returns a string representation of the event kind.
+func (k EventKind) String() string {
+	switch k {
+	case EventKindStarted:
+		return "started"
+	case EventKindFailedToStart:
+		return "failed to start"
+	case EventKindStopped:
+		return "stopped"
+	case EventKindUpdated:
+		return "updated"
+	case EventKindConfigUpdated:
+		return "config updated"
+	case EventKindVersionMismatch:
+		return "version mismatch"
+	default:
+		return "unknown"
+	}
+}
+
+// Kind returns the kind of the event.
+func (ev *Event) Kind() EventKind {
+	switch {
+	case ev.Started != nil:
+		return EventKindStarted
+	case ev.FailedToStart != nil:
+		return EventKindFailedToStart
+	case ev.Stopped != nil:
+		return EventKindStopped
+	case ev.Updated != nil:
+		return EventKindUpdated
+	case ev.ConfigUpdated != nil:
+		return EventKindConfigUpdated
+	case ev.VersionMismatch != nil:
+		return EventKindVersionMismatch
+	default:
+		return EventKindUnknown
+	}
+}
+
+type filteredSubscription struct {
+	sub       pubsub.ClosableSubscription
+	closeOnce sync.Once
+	quitCh    chan struct{}
+}
+
+func (s *filteredSubscription) Close() {
+	s.closeOnce.Do(func() {
+		close(s.quitCh)
+		s.sub.Close()
+	})
+}
+
+// FilterEvents forwards only events of the given kinds from the given event subscription.
+//
+// Closing the returned subscription closes the underlying one and stops forwarding, even in case
+// the consumer has stopped reading events.
+func FilterEvents(ch <-chan *Event, sub pubsub.ClosableSubscription, kinds ...EventKind) (<-chan *Event, pubsub.ClosableSubscription) {
+	fsub := &filteredSubscription{
+		sub:    sub,
+		quitCh: make(chan struct{}),
+	}
+	filteredCh := make(chan *Event)
+
+	go func() {
+		defer close(filteredCh)
+		// Drain the underlying channel until it is closed so that its forwarder can exit.
+		defer func() {
+			for range ch { // nolint: revive
+			}
+		}()
+
+		for {
+			select {
+			case ev, ok := <-ch:
+				if !ok {
+					return
+				}
+				if !slices.Contains(kinds, ev.Kind()) {
+					continue
+				}
+
+				select {
+				case filteredCh <- ev:
+				case <-fsub.quitCh:
+					return
+				}
+			case <-fsub.quitCh:
+				return
+			}
+		}
+	}()
+
+	return filteredCh, fsub
 }
 
 // StartedEvent is a runtime started event.
@@ -112,10 +283,24 @@ type StartedEvent struct {
 type FailedToStartEvent struct {
 	// Error is the error that has occurred.
 	Error error
+
+	// Stderr are the last lines the runtime wrote to its standard error output, if available.
+	Stderr []string
+
+	// Permanent is true when the failure is permanent and no further start attempts will be made.
+	Permanent bool
 }
 
 // StoppedEvent is a runtime stopped event.
-type StoppedEvent struct{}
+type StoppedEvent struct {
+	// Stderr are the last lines the runtime wrote to its standard error output before it
+	// terminated unexpectedly, if available.
+	Stderr []string
+
+	// Idle is true in case the runtime process has been stopped due to being idle. Such a runtime
+	// remains available as it is started again on the next call.
+	Idle bool
+}
 
 // UpdatedEvent is a runtime metadata updated event.
 type UpdatedEvent struct {
@@ -125,6 +310,57 @@ type UpdatedEvent struct {
 	// CapabilityTEE is the updated runtime's CapabilityTEE. It may be nil in case the runtime is
 	// not running inside a TEE.
 	CapabilityTEE *node.CapabilityTEE
+
+	// Changed describes which parts of the CapabilityTEE have changed compared to the previous
+	// CapabilityTEE of the runtime.
+	Changed CapabilityTEEChange
+}
+
+// CapabilityTEEChange is a bitmask describing which parts of a CapabilityTEE have changed.
+type CapabilityTEEChange uint8
+
+const (
+	// CapabilityTEEChangeHardware indicates that the TEE hardware has changed.
+	CapabilityTEEChangeHardware CapabilityTEEChange = 1 << iota
+	// CapabilityTEEChangeRAK indicates that the runtime attestation key has changed.
+	CapabilityTEEChangeRAK
+	// CapabilityTEEChangeREK indicates that the runtime encryption key has changed.
+	CapabilityTEEChangeREK
+	// CapabilityTEEChangeAttestation indicates that the attestation has changed.
+	CapabilityTEEChangeAttestation
+)
+
+// Has checks whether all of the given changes are set.
+func (c CapabilityTEEChange) Has(changes CapabilityTEEChange) bool {
+	return c&changes == changes
+}
+
+// DiffCapabilityTEE computes which parts of the CapabilityTEE have changed. A nil CapabilityTEE
+// is treated the same as an empty one.
+func DiffCapabilityTEE(prev, next *node.CapabilityTEE) CapabilityTEEChange {
+	if prev == nil {
+		prev = &node.CapabilityTEE{}
+	}
+	if next == nil {
+		next = &node.CapabilityTEE{}
+	}
+
+	var changes CapabilityTEEChange
+	if prev.Hardware != next.Hardware {
+		changes |= CapabilityTEEChangeHardware
+	}
+	if !prev.RAK.Equal(next.RAK) {
+		changes |= CapabilityTEEChangeRAK
+	}
+	switch {
+	case prev.REK == nil && next.REK == nil:
+	case prev.REK == nil, next.REK == nil, *prev.REK != *next.REK:
+		changes |= CapabilityTEEChangeREK
+	}
+	if !bytes.Equal(prev.Attestation, next.Attestation) {
+		changes |= CapabilityTEEChangeAttestation
+	}
+	return changes
 }
 
 // ConfigUpdatedEvent is a runtime configuration updated event.
@@ -132,3 +368,15 @@ type UpdatedEvent struct {
 // This event can be used by runtime host implementations to signal that the underlying runtime
 // configuration has changed and some things (e.g. registration) may need a refresh.
 type ConfigUpdatedEvent struct{}
+
+// VersionMismatchEvent is a runtime version mismatch event.
+//
+// This event is emitted when the started runtime reports a version that differs from the one
+// configured in the bundle. It is followed by a FailedToStartEvent.
+type VersionMismatchEvent struct {
+	// Reported is the version reported by the runtime.
+	Reported version.Version
+
+	// Expected is the version configured in the bundle.
+	Expected version.Version
+}
@@ -0,0 +1,128 @@
+package host
+
+import (
+	"testing"
+	"time"
+
+	"github.com/oasisprotocol/curve25519-voi/primitives/x25519"
+	"github.com/stretchr/testify/require"
+
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
+)
+
+func TestEventKind(t *testing.T) {
+	require := require.New(t)
+
+	for _, tc := range []struct {
+		ev   *Event
+		kind EventKind
+	}{
+		{&Event{}, EventKindUnknown},
+		{&Event{Started: &StartedEvent{}}, EventKindStarted},
+		{&Event{FailedToStart: &FailedToStartEvent{}}, EventKindFailedToStart},
+		{&Event{Stopped: &StoppedEvent{}}, EventKindStopped},
+		{&Event{Updated: &UpdatedEvent{}}, EventKindUpdated},
+		{&Event{ConfigUpdated: &ConfigUpdatedEvent{}}, EventKindConfigUpdated},
+		{&Event{VersionMismatch: &VersionMismatchEvent{}}, EventKindVersionMismatch},
+	} {
+		require.Equal(tc.kind, tc.ev.Kind(), "event kind for %s", tc.kind)
+	}
+}
+
+func TestDiffCapabilityTEE(t *testing.T) {
+	require := require.New(t)
+
+	rak := memorySigner.NewTestSigner("host test RAK").Public()
+	otherRak := memorySigner.NewTestSigner("host test other RAK").Public()
+	rek := x25519.PublicKey{1}
+	otherRek := x25519.PublicKey{2}
+
+	base := node.CapabilityTEE{
+		Hardware:    node.TEEHardwareIntelSGX,
+		RAK:         rak,
+		REK:         &rek,
+		Attestation: []byte("quote"),
+	}
+
+	for _, tc := range []struct {
+		name    string
+		modify  func(c *node.CapabilityTEE)
+		changed CapabilityTEEChange
+	}{
+		{"Unchanged", func(*node.CapabilityTEE) {}, 0},
+		{"Quote", func(c *node.CapabilityTEE) { c.Attestation = []byte("new quote") }, CapabilityTEEChangeAttestation},
+		{"RAK", func(c *node.CapabilityTEE) { c.RAK = otherRak }, CapabilityTEEChangeRAK},
+		{"REK", func(c *node.CapabilityTEE) { c.REK = &otherRek }, CapabilityTEEChangeREK},
+		{"NoREK", func(c *node.CapabilityTEE) { c.REK = nil }, CapabilityTEEChangeREK},
+		{"Hardware", func(c *node.CapabilityTEE) { c.Hardware = node.TEEHardwareInvalid }, CapabilityTEEChangeHardware},
+		{
+			"RAKAndQuote",
+			func(c *node.CapabilityTEE) {
+				c.RAK = otherRak
+				c.Attestation = nil
+			},
+			CapabilityTEEChangeRAK | CapabilityTEEChangeAttestation,
+		},
+	} {
+		updated := base
+		tc.modify(&updated)
+
+		changed := DiffCapabilityTEE(&base, &updated)
+		require.Equal(tc.changed, changed, tc.name)
+		require.True(changed.Has(tc.changed), tc.name)
+	}
+
+	// Only the quote has changed.
+	updated := base
+	updated.Attestation = []byte("new quote")
+	changed := DiffCapabilityTEE(&base, &updated)
+	require.True(changed.Has(CapabilityTEEChangeAttestation), "quote change should be reported")
+	require.False(changed.Has(CapabilityTEEChangeRAK), "RAK change should not be reported")
+
+	// A missing CapabilityTEE is treated as an empty one.
+	require.Equal(CapabilityTEEChange(0), DiffCapabilityTEE(nil, nil))
+	require.Equal(
+		CapabilityTEEChangeHardware|CapabilityTEEChangeRAK|CapabilityTEEChangeREK|CapabilityTEEChangeAttestation,
+		DiffCapabilityTEE(nil, &base),
+	)
+}
+
+func TestFilterEvents(t *testing.T) {
+	require := require.New(t)
+
+	notifier := pubsub.NewBroker(false)
+	typedCh := make(chan *Event)
+	notifierSub := notifier.Subscribe()
+	notifierSub.Unwrap(typedCh)
+
+	ch, sub := FilterEvents(typedCh, notifierSub, EventKindStopped)
+	notifier.Broadcast(&Event{Started: &StartedEvent{}})
+	notifier.Broadcast(&Event{Stopped: &StoppedEvent{}})
+
+	select {
+	case ev := <-ch:
+		require.Equal(EventKindStopped, ev.Kind(), "only stopped events should be delivered")
+	case <-time.After(time.Second):
+		t.Fatalf("failed to receive stopped event")
+	}
+
+	// Stop reading while events are still being emitted and make sure that closing the
+	// subscription terminates forwarding.
+	for i := 0; i < 10; i++ {
+		notifier.Broadcast(&Event{Stopped: &StoppedEvent{}})
+	}
+	time.Sleep(100 * time.Millisecond)
+	sub.Close()
+	sub.Close() // Closing twice should be safe.
+
+	// Give the forwarding goroutine some time to notice, it must not deliver any more events.
+	time.Sleep(100 * time.Millisecond)
+	select {
+	case _, ok := <-ch:
+		require.False(ok, "no events should be delivered after the subscription was closed")
+	case <-time.After(time.Second):
+		t.Fatalf("forwarding goroutine leaked after subscription was closed")
+	}
+}
@@ -61,6 +61,14 @@ func (lb *lbRuntime) GetCapabilityTEE() (*node.CapabilityTEE, error) {
 	return lb.instances[0].GetCapabilityTEE()
 }
 
+// Implements host.Runtime.
+//
+// Only information about the process hosting the primary (first) instance is returned, as this
+// is the instance that is used for everything other than load-balanced requests.
+func (lb *lbRuntime) GetProcessInfo() (*host.ProcessInfo, error) {
+	return lb.instances[0].GetProcessInfo()
+}
+
 // shouldPropagateToAll checks whether the given runtime request should be propagated to all
 // instances.
 func shouldPropagateToAll(body *protocol.Body) bool {
@@ -163,6 +171,12 @@ func (lb *lbRuntime) WatchEvents() (<-chan *host.Event, pubsub.ClosableSubscript
 	return lb.instances[0].WatchEvents()
 }
 
+// Implements host.Runtime.
+func (lb *lbRuntime) WatchEventsFiltered(kinds ...host.EventKind) (<-chan *host.Event, pubsub.ClosableSubscription) {
+	ch, sub := lb.WatchEvents()
+	return host.FilterEvents(ch, sub, kinds...)
+}
+
 // Implements host.Runtime.
 func (lb *lbRuntime) Start() {
 	lb.startOnce.Do(func() {
@@ -191,6 +205,9 @@ func (lb *lbRuntime) Start() {
 							lb.l.Lock()
 							lb.healthyInstances[idx] = struct{}{}
 							lb.l.Unlock()
+						case ev.Stopped != nil && ev.Stopped.Idle:
+							// Instances stopped due to being idle are started again on the next
+							// call, so they remain available.
 						case ev.FailedToStart != nil, ev.Stopped != nil:
 							// Mark instance as failed.
 							lb.logger.Warn("instance is no longer available",
@@ -242,6 +259,17 @@ func (lb *lbRuntime) Abort(ctx context.Context, force bool) error {
 	return anyErr
 }
 
+// Implements host.Runtime.
+func (lb *lbRuntime) Restart(ctx context.Context) error {
+	// Restart instances one by one so that the remaining instances can keep serving requests.
+	for _, rt := range lb.instances {
+		if err := rt.Restart(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Implements host.Runtime.
 func (lb *lbRuntime) Stop() {
 	lb.stopOnce.Do(func() {
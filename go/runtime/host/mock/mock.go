@@ -73,6 +73,11 @@ func (r *runtime) GetCapabilityTEE() (*node.CapabilityTEE, error) {
 	return nil, nil
 }
 
+// Implements host.Runtime.
+func (r *runtime) GetProcessInfo() (*host.ProcessInfo, error) {
+	return nil, host.ErrNoProcess
+}
+
 // Implements host.Runtime.
 func (r *runtime) Call(ctx context.Context, body *protocol.Body) (*protocol.Body, error) {
 	switch {
@@ -202,6 +207,12 @@ func (r *runtime) WatchEvents() (<-chan *host.Event, pubsub.ClosableSubscription
 	return typedCh, sub
 }
 
+// Implements host.Runtime.
+func (r *runtime) WatchEventsFiltered(kinds ...host.EventKind) (<-chan *host.Event, pubsub.ClosableSubscription) {
+	ch, sub := r.WatchEvents()
+	return host.FilterEvents(ch, sub, kinds...)
+}
+
 // Implements host.Runtime.
 func (r *runtime) Start() {
 	r.notifier.Broadcast(&host.Event{
@@ -214,6 +225,17 @@ func (r *runtime) Abort(context.Context, bool) error {
 	return nil
 }
 
+// Implements host.Runtime.
+func (r *runtime) Restart(context.Context) error {
+	r.notifier.Broadcast(&host.Event{
+		Stopped: &host.StoppedEvent{},
+	})
+	r.notifier.Broadcast(&host.Event{
+		Started: &host.StartedEvent{},
+	})
+	return nil
+}
+
 // Implements host.Runtime.
 func (r *runtime) Stop() {
 	r.notifier.Broadcast(&host.Event{
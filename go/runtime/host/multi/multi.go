@@ -60,8 +60,9 @@ func (ah *aggregatedHost) startDiscard() {
 				case ev.Started != nil:
 					// Store the last started event.
 					startedEv = ev
-				case ev.Stopped != nil:
-					// Clear out if any stopped event received.
+				case ev.Stopped != nil && !ev.Stopped.Idle:
+					// Clear out if any stopped event received. Runtimes stopped due to being
+					// idle are started again on the next call, so they remain available.
 					startedEv = nil
 				case ev.Updated != nil && startedEv != nil:
 					// Make sure the started event's CapabilityTEE is always the latest one.
@@ -161,6 +162,15 @@ func (agg *Aggregate) GetCapabilityTEE() (*node.CapabilityTEE, error) {
 	return active.host.GetCapabilityTEE()
 }
 
+// GetProcessInfo implements host.Runtime.
+func (agg *Aggregate) GetProcessInfo() (*host.ProcessInfo, error) {
+	active, err := agg.getActiveHost()
+	if err != nil {
+		return nil, err
+	}
+	return active.host.GetProcessInfo()
+}
+
 // shouldPropagateToNextVersion checks whether the given runtime request should also be propagated
 // to the next version that is pending activation.
 func shouldPropagateToNextVersion(body *protocol.Body) bool {
@@ -245,6 +255,12 @@ func (agg *Aggregate) WatchEvents() (<-chan *host.Event, pubsub.ClosableSubscrip
 	return typedCh, sub
 }
 
+// WatchEventsFiltered implements host.Runtime.
+func (agg *Aggregate) WatchEventsFiltered(kinds ...host.EventKind) (<-chan *host.Event, pubsub.ClosableSubscription) {
+	ch, sub := agg.WatchEvents()
+	return host.FilterEvents(ch, sub, kinds...)
+}
+
 // Start implements host.Runtime.
 func (agg *Aggregate) Start() {
 	agg.l.RLock()
@@ -267,6 +283,15 @@ func (agg *Aggregate) Abort(ctx context.Context, force bool) error {
 	return active.host.Abort(ctx, force)
 }
 
+// Restart implements host.Runtime.
+func (agg *Aggregate) Restart(ctx context.Context) error {
+	active, err := agg.getActiveHost()
+	if err != nil {
+		return err
+	}
+	return active.host.Restart(ctx)
+}
+
 // Stop implements host.Runtime.
 func (agg *Aggregate) Stop() {
 	agg.l.Lock()
@@ -390,7 +415,7 @@ func (agg *Aggregate) stopActiveLocked() {
 	for {
 		ev := <-ah.ch
 		agg.notifier.Broadcast(ev) // Propagate
-		if ev.Stopped == nil {
+		if ev.Stopped == nil || ev.Stopped.Idle {
 			continue
 		}
 		agg.logger.Debug("stopActiveLocked: stopped old sub-host",
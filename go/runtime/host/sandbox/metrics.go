@@ -0,0 +1,53 @@
+package sandbox
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/metrics"
+)
+
+var (
+	// Number of runtime process restarts.
+	restartCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_runtime_host_restart_count",
+			Help: "Number of times the runtime process has been restarted after termination.",
+		},
+		[]string{"runtime"},
+	)
+
+	// Current runtime process start attempt.
+	currentAttempt = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "oasis_runtime_host_current_attempt",
+			Help: "Current runtime process start attempt since the last backoff reset.",
+		},
+		[]string{"runtime"},
+	)
+
+	sandboxCollectors = []prometheus.Collector{
+		restartCount,
+		currentAttempt,
+	}
+
+	metricsOnce sync.Once
+)
+
+func (r *sandboxedRuntime) getMetricLabels() prometheus.Labels {
+	return prometheus.Labels{
+		"runtime": r.id.String(),
+	}
+}
+
+// initMetrics registers the metrics collectors if metrics are enabled.
+func initMetrics() {
+	if !metrics.Enabled() {
+		return
+	}
+
+	metricsOnce.Do(func() {
+		prometheus.MustRegister(sandboxCollectors...)
+	})
+}
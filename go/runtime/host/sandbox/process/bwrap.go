@@ -186,6 +186,20 @@ func NewBubbleWrap(cfg Config) (Process, error) {
 	if err != nil {
 		return nil, err
 	}
+	nk := n.(*naked)
+
+	// Apply resource limits before the sandbox is configured and the binary is started.
+	cgroupDir, err := limitResources(nk.GetPID(), &cfg)
+	if err != nil {
+		nk.Kill()
+		return nil, fmt.Errorf("sandbox: failed to apply resource limits: %w", err)
+	}
+	if cgroupDir != "" {
+		go func() {
+			<-nk.Wait()
+			_ = os.Remove(cgroupDir)
+		}()
+	}
 
 	// Send configuration arguments.
 	for _, arg := range fdArgs {
@@ -215,5 +229,5 @@ func NewBubbleWrap(cfg Config) (Process, error) {
 		}
 	}
 
-	return &bwrap{n.(*naked)}, nil
+	return &bwrap{nk}, nil
 }
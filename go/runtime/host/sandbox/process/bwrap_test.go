@@ -1,9 +1,24 @@
 package process
 
-import "testing"
+import (
+	"os"
+	"testing"
+)
+
+const bwrapPath = "/usr/bin/bwrap"
 
 func TestBubbleWrapSandbox(t *testing.T) {
 	t.Run("BindData", func(t *testing.T) {
-		testBindData(t, NewBubbleWrap, "/usr/bin/bwrap")
+		testBindData(t, NewBubbleWrap, bwrapPath)
+	})
+	t.Run("Terminate", func(t *testing.T) {
+		if _, err := os.Stat(bwrapPath); err != nil {
+			t.Skip("bwrap not available")
+		}
+
+		// The sandboxed binary itself traps SIGTERM, bwrap must not swallow it.
+		testTerminateTrap(t, NewBubbleWrap, bwrapPath,
+			`trap "echo terminated > \"$MARKER\"; exit 0" TERM; while :; do :; done`,
+		)
 	})
 }
@@ -0,0 +1,89 @@
+//go:build linux
+// +build linux
+
+package process
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// cgroupCPUPeriod is the CPU bandwidth period used for CPU limits (in microseconds).
+const cgroupCPUPeriod = 100_000
+
+// applyCgroupLimits creates a new cgroup for the given process under the given parent cgroup,
+// configures the resource limits and moves the process into it. It returns the path to the
+// created cgroup which should be removed after the process terminates.
+func applyCgroupLimits(parentDir string, pid int, cfg *Config) (string, error) {
+	// Under cgroups v2 controllers can only be enabled for child groups of a group that does not
+	// contain any processes, so make sure we have been given a dedicated group.
+	procs, err := os.ReadFile(filepath.Join(parentDir, "cgroup.procs"))
+	if err != nil {
+		return "", fmt.Errorf("failed to read parent cgroup: %w", err)
+	}
+	if len(strings.TrimSpace(string(procs))) > 0 {
+		return "", fmt.Errorf("parent cgroup %s contains processes", parentDir)
+	}
+
+	// Make sure the required controllers are available to child cgroups.
+	var controllers []string
+	if cfg.CPUQuota > 0 {
+		controllers = append(controllers, "+cpu")
+	}
+	if cfg.MemoryLimitBytes > 0 {
+		controllers = append(controllers, "+memory")
+	}
+	if err = writeCgroupFile(parentDir, "cgroup.subtree_control", strings.Join(controllers, " ")); err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(parentDir, fmt.Sprintf("oasis-runtime-%d", pid))
+	if err = os.Mkdir(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create cgroup: %w", err)
+	}
+
+	if err := func() error {
+		if cfg.CPUQuota > 0 {
+			quota := uint64(math.Ceil(cfg.CPUQuota * cgroupCPUPeriod))
+			if err := writeCgroupFile(dir, "cpu.max", fmt.Sprintf("%d %d", quota, cgroupCPUPeriod)); err != nil {
+				return err
+			}
+		}
+		if cfg.MemoryLimitBytes > 0 {
+			if err := writeCgroupFile(dir, "memory.max", strconv.FormatUint(cfg.MemoryLimitBytes, 10)); err != nil {
+				return err
+			}
+		}
+		return writeCgroupFile(dir, "cgroup.procs", strconv.Itoa(pid))
+	}(); err != nil {
+		_ = os.Remove(dir)
+		return "", err
+	}
+
+	return dir, nil
+}
+
+func writeCgroupFile(dir, name, value string) error {
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(value), 0o644); err != nil { // nolint: gosec
+		return fmt.Errorf("failed to write cgroup file %s: %w", path, err)
+	}
+	return nil
+}
+
+// limitResources applies any configured resource limits to the given process. It returns the
+// path to the created cgroup (if any) which should be removed after the process terminates.
+func limitResources(pid int, cfg *Config) (string, error) {
+	if cfg.CPUQuota <= 0 && cfg.MemoryLimitBytes == 0 {
+		return "", nil
+	}
+
+	if cfg.CgroupParent == "" {
+		return "", fmt.Errorf("no parent cgroup configured")
+	}
+	return applyCgroupLimits(cfg.CgroupParent, pid, cfg)
+}
@@ -0,0 +1,84 @@
+//go:build linux
+// +build linux
+
+package process
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCgroupLimits(t *testing.T) {
+	require := require.New(t)
+
+	// Use a temporary directory in place of the parent cgroup so that the test does not
+	// require cgroup delegation.
+	parentDir, err := os.MkdirTemp("", "oasis-runtime-host-sandbox-cgroup-test_")
+	require.NoError(err, "MkdirTemp")
+	defer os.RemoveAll(parentDir)
+	require.NoError(os.WriteFile(filepath.Join(parentDir, "cgroup.procs"), nil, 0o644), "WriteFile")
+
+	p, err := NewNaked(Config{
+		Path: "/bin/sleep",
+		Args: []string{"60"},
+	})
+	require.NoError(err, "NewNaked")
+	defer p.Kill()
+
+	cfg := Config{
+		CPUQuota:         1.5,
+		MemoryLimitBytes: 256 * 1024 * 1024,
+	}
+	dir, err := applyCgroupLimits(parentDir, p.GetPID(), &cfg)
+	require.NoError(err, "applyCgroupLimits")
+	require.Equal(filepath.Join(parentDir, fmt.Sprintf("oasis-runtime-%d", p.GetPID())), dir)
+
+	for _, tc := range []struct {
+		path     string
+		expected string
+	}{
+		{filepath.Join(parentDir, "cgroup.subtree_control"), "+cpu +memory"},
+		{filepath.Join(dir, "cpu.max"), "150000 100000"},
+		{filepath.Join(dir, "memory.max"), "268435456"},
+		{filepath.Join(dir, "cgroup.procs"), strconv.Itoa(p.GetPID())},
+	} {
+		data, err := os.ReadFile(tc.path)
+		require.NoError(err, "ReadFile(%s)", tc.path)
+		require.Equal(tc.expected, string(data), "cgroup file %s", tc.path)
+	}
+
+	// Only configured limits should be written.
+	require.NoError(os.RemoveAll(dir), "RemoveAll")
+	cfg = Config{MemoryLimitBytes: 1024}
+	dir, err = applyCgroupLimits(parentDir, p.GetPID(), &cfg)
+	require.NoError(err, "applyCgroupLimits")
+	require.NoFileExists(filepath.Join(dir, "cpu.max"))
+	require.FileExists(filepath.Join(dir, "memory.max"))
+
+	// No limits should not create a cgroup.
+	dir, err = limitResources(p.GetPID(), &Config{})
+	require.NoError(err, "limitResources")
+	require.Empty(dir)
+
+	// Limits require a parent cgroup to be configured.
+	_, err = limitResources(p.GetPID(), &Config{MemoryLimitBytes: 1024})
+	require.Error(err, "limitResources should fail without a parent cgroup")
+
+	require.NoError(os.RemoveAll(filepath.Join(parentDir, fmt.Sprintf("oasis-runtime-%d", p.GetPID()))), "RemoveAll")
+	dir, err = limitResources(p.GetPID(), &Config{MemoryLimitBytes: 1024, CgroupParent: parentDir})
+	require.NoError(err, "limitResources")
+	require.FileExists(filepath.Join(dir, "memory.max"))
+
+	// A parent cgroup containing processes (e.g., the one the node is running in) cannot enable
+	// controllers for its children and should be rejected.
+	require.NoError(os.RemoveAll(dir), "RemoveAll")
+	require.NoError(os.WriteFile(filepath.Join(parentDir, "cgroup.procs"), []byte(strconv.Itoa(os.Getpid())+"\n"), 0o644), "WriteFile")
+	_, err = applyCgroupLimits(parentDir, p.GetPID(), &cfg)
+	require.Error(err, "applyCgroupLimits should reject a parent cgroup containing processes")
+	require.NoDirExists(filepath.Join(parentDir, fmt.Sprintf("oasis-runtime-%d", p.GetPID())))
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+// +build !linux
+
+package process
+
+import "errors"
+
+func limitResources(_ int, cfg *Config) (string, error) {
+	if cfg.CPUQuota <= 0 && cfg.MemoryLimitBytes == 0 {
+		return "", nil
+	}
+	return "", errors.New("resource limits only implemented for Linux")
+}
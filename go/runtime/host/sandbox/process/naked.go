@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"sync"
 	"syscall"
+	"time"
 )
 
 type naked struct {
@@ -48,6 +49,24 @@ func (n *naked) Kill() {
 	_ = syscall.Kill(-n.cmd.Process.Pid, syscall.SIGKILL)
 }
 
+// Implements Process.
+func (n *naked) Terminate(grace time.Duration) {
+	// Signal the whole process group, same as Kill, so that processes spawned by a sandbox
+	// binary (e.g., the runtime under bwrap) also get a chance to shut down cleanly.
+	if err := syscall.Kill(-n.cmd.Process.Pid, syscall.SIGTERM); err == nil {
+		timer := time.NewTimer(grace)
+		defer timer.Stop()
+
+		select {
+		case <-n.waitCh:
+			return
+		case <-timer.C:
+		}
+	}
+
+	n.Kill()
+}
+
 func (n *naked) wait() error {
 	err := n.cmd.Wait()
 	if err != nil {
@@ -88,6 +107,8 @@ func NewNaked(cfg Config) (Process, error) {
 	}
 	cmd.Stderr = cfg.Stderr
 	cmd.ExtraFiles = cfg.extraFiles
+	// Run in a separate process group so that the whole group can be signalled.
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
 
 	// Write any bound data to respective files.
 	for path, reader := range cfg.BindData {
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -14,6 +15,15 @@ func TestNakedSandbox(t *testing.T) {
 	t.Run("BindData", func(t *testing.T) {
 		testBindData(t, NewNaked, "")
 	})
+	t.Run("Terminate", func(t *testing.T) {
+		testTerminate(t, NewNaked, "")
+	})
+	t.Run("TerminateGroup", func(t *testing.T) {
+		// The child shell dies on SIGTERM, only its background job traps it.
+		testTerminateTrap(t, NewNaked, "",
+			`/bin/sh -c 'trap "echo terminated > \"$MARKER\"; exit 0" TERM; while :; do sleep 0.1; done' & wait`,
+		)
+	})
 }
 
 func testBindData(t *testing.T, factory func(Config) (Process, error), sandboxBinary string) {
@@ -46,3 +56,61 @@ func testBindData(t *testing.T, factory func(Config) (Process, error), sandboxBi
 	// Make sure output was correct.
 	require.EqualValues("hello world", stdout.Bytes())
 }
+
+func testTerminate(t *testing.T, factory func(Config) (Process, error), sandboxBinary string) {
+	require := require.New(t)
+
+	// Run a process that exits cleanly on SIGTERM.
+	p, err := factory(Config{
+		Path:              "/bin/sh",
+		Args:              []string{"-c", "trap 'exit 0' TERM; while :; do sleep 0.1; done"},
+		SandboxBinaryPath: sandboxBinary,
+	})
+	require.NoError(err, "NewNaked")
+
+	// Give the shell some time to install the signal handler.
+	time.Sleep(200 * time.Millisecond)
+
+	p.Terminate(10 * time.Second)
+
+	select {
+	case <-p.Wait():
+	default:
+		t.Fatalf("process should be terminated")
+	}
+	require.NoError(p.Error(), "process should exit on SIGTERM without being killed")
+}
+
+func testTerminateTrap(t *testing.T, factory func(Config) (Process, error), sandboxBinary, script string) {
+	require := require.New(t)
+
+	dir, err := os.MkdirTemp("", "oasis-runtime-host-sandbox-test_")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dir)
+
+	marker := filepath.Join(dir, "marker")
+
+	// Run a process that writes a marker once it receives SIGTERM.
+	p, err := factory(Config{
+		Path: "/bin/sh",
+		Args: []string{"-c", script},
+		Env: map[string]string{
+			"MARKER": marker,
+		},
+		BindRW: map[string]string{
+			dir: dir,
+		},
+		SandboxBinaryPath: sandboxBinary,
+	})
+	require.NoError(err, "NewNaked")
+
+	// Give the shell some time to install the signal handler.
+	time.Sleep(200 * time.Millisecond)
+
+	p.Terminate(10 * time.Second)
+
+	require.Eventually(func() bool {
+		data, err := os.ReadFile(marker)
+		return err == nil && string(data) == "terminated\n"
+	}, 5*time.Second, 50*time.Millisecond, "process should receive SIGTERM")
+}
@@ -4,6 +4,7 @@ package process
 import (
 	"io"
 	"os"
+	"time"
 )
 
 // Config contains the sandbox configuration.
@@ -42,6 +43,21 @@ type Config struct {
 	// SandboxBinaryPath is the path to the sandbox support binary.
 	SandboxBinaryPath string
 
+	// CPUQuota is the maximum number of CPUs the sandboxed process may use (e.g., 1.5 allows the
+	// process to use one and a half CPUs). Zero means no limit. Only supported by the Bubblewrap
+	// sandbox on hosts using cgroups v2.
+	CPUQuota float64
+
+	// MemoryLimitBytes is the maximum amount of memory the sandboxed process may use. Zero means
+	// no limit. Only supported by the Bubblewrap sandbox on hosts using cgroups v2.
+	MemoryLimitBytes uint64
+
+	// CgroupParent is the path to a cgroups v2 group delegated to the node under which a cgroup
+	// is created for each sandboxed process. It must not contain any processes itself as
+	// controllers cannot be enabled for child groups otherwise. Required in case any resource
+	// limits are configured.
+	CgroupParent string
+
 	extraFiles []*os.File
 }
 
@@ -60,4 +76,8 @@ type Process interface {
 
 	// Kill causes the sandboxed process to exit immediately.
 	Kill()
+
+	// Terminate asks the sandboxed process to exit and waits for the given grace period for it
+	// to do so, after which the process is killed.
+	Terminate(grace time.Duration)
 }
@@ -5,9 +5,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
@@ -24,24 +26,38 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/runtime/host/sandbox/process"
 )
 
-var errRuntimeNotReady = errors.New("runtime is not yet ready")
+var (
+	errRuntimeNotReady   = errors.New("runtime is not yet ready")
+	errRuntimeNotStopped = errors.New("runtime did not terminate after being killed")
+	errRuntimeStopped    = errors.New("runtime has been stopped")
+)
 
 const (
-	runtimeConnectTimeout      = 5 * time.Second
-	runtimeInitTimeout         = 1 * time.Second
-	runtimeExtendedInitTimeout = 120 * time.Second
-	runtimeInterruptTimeout    = 1 * time.Second
-	resetTickerTimeout         = 15 * time.Minute
+	defaultRuntimeConnectTimeout   = 5 * time.Second
+	runtimeInitTimeout             = 1 * time.Second
+	runtimeExtendedInitTimeout     = 120 * time.Second
+	defaultRuntimeInterruptTimeout = 1 * time.Second
+	defaultRuntimeKillTimeout      = 10 * time.Second
+	defaultTerminationGracePeriod  = 5 * time.Second
+	defaultHealthCheckTimeout      = 5 * time.Second
+	resetTickerTimeout             = 15 * time.Minute
+	standbyRetryInterval           = 5 * time.Second
+
+	defaultHealthCheckFailureThreshold = 3
+	defaultStderrTailLines             = 20
 
 	bindHostSocketPath = "/host.sock"
 
+	envWorkerHost = "OASIS_WORKER_HOST"
+
 	ctrlChannelBufferSize = 16
 )
 
 // Config contains the sandbox provisioner configuration options.
 type Config struct {
 	// GetSandboxConfig is a function that generates the sandbox configuration. In case it is not
-	// specified a default function is used.
+	// specified a default function is used. Extra environment variables and read-only bind mounts
+	// requested via host.Config are merged into the generated configuration in either case.
 	GetSandboxConfig func(cfg host.Config, socketPath, runtimeDir string) (process.Config, error)
 
 	// HostInfo provides information about the host environment.
@@ -58,10 +74,175 @@ type Config struct {
 	// SandboxBinaryPath is the path to the sandbox support binary.
 	SandboxBinaryPath string
 
+	// RuntimeConnectTimeout is the maximum amount of time to wait for the runtime to connect
+	// after it has been started. In case it is not specified a default timeout is used.
+	RuntimeConnectTimeout time.Duration
+
+	// RuntimeInterruptTimeout is the maximum amount of time to wait for the runtime to respond
+	// to an abort request before it is killed. In case it is not specified a default timeout is
+	// used.
+	RuntimeInterruptTimeout time.Duration
+
+	// RuntimeKillTimeout is the maximum amount of time to wait for the runtime to terminate after
+	// it has been killed. In case it is not specified a default timeout is used.
+	RuntimeKillTimeout time.Duration
+
+	// TerminationGracePeriod is the amount of time the runtime is given to exit after being asked
+	// to terminate when it is stopped, after which it is killed. In case it is not specified a
+	// default grace period is used.
+	TerminationGracePeriod time.Duration
+
+	// PersistentRuntimeDir is an optional base directory for runtime directories that persist
+	// across runtime restarts. In case it is specified, each runtime uses a directory named after
+	// its identifier under the base directory, which is only removed when the runtime is stopped.
+	// Otherwise, a new temporary directory is used on each start.
+	PersistentRuntimeDir string
+
+	// MaxConcurrentRequests is the maximum number of concurrent in-flight requests to the runtime.
+	// Additional callers wait until a request completes. Zero means no limit.
+	MaxConcurrentRequests int
+
+	// HealthCheckInterval is the interval at which the runtime is pinged to make sure that it is
+	// still responsive. Health checks are skipped while runtime calls are in flight. Zero disables
+	// health checks.
+	HealthCheckInterval time.Duration
+
+	// HealthCheckTimeout is the maximum amount of time to wait for the runtime to respond to a
+	// health check. In case it is not specified a default timeout is used.
+	HealthCheckTimeout time.Duration
+
+	// HealthCheckFailureThreshold is the number of consecutive failed health checks after which
+	// the runtime is restarted. In case it is not specified a default threshold is used.
+	HealthCheckFailureThreshold int
+
+	// CPUQuota is the maximum number of CPUs a sandboxed runtime may use. Zero means no limit.
+	//
+	// Limits are enforced via cgroups v2 and require CgroupParent to be set. They are ignored
+	// when InsecureNoSandbox is set.
+	CPUQuota float64
+
+	// MemoryLimitBytes is the maximum amount of memory a sandboxed runtime may use. Zero means
+	// no limit.
+	//
+	// Limits are enforced via cgroups v2 and require CgroupParent to be set. They are ignored
+	// when InsecureNoSandbox is set.
+	MemoryLimitBytes uint64
+
+	// CgroupParent is the path to a cgroups v2 group delegated to the node, under which a cgroup
+	// is created for each sandboxed runtime. It must be a dedicated group that does not contain
+	// any processes (e.g., not the group the node itself is running in), as the cpu and memory
+	// controllers cannot be enabled for its child groups otherwise.
+	CgroupParent string
+
+	// RestartInitialInterval is the initial interval between runtime restart attempts. In case it
+	// is not specified a default interval is used.
+	RestartInitialInterval time.Duration
+
+	// RestartMaxInterval is the maximum interval between runtime restart attempts. In case it is
+	// not specified a default interval is used.
+	RestartMaxInterval time.Duration
+
+	// RestartRandomizationFactor is the jitter applied to restart intervals so that runtimes
+	// which crash at the same time do not restart in lockstep. Each interval is randomized
+	// within [interval * (1 - factor), interval * (1 + factor)]. In case it is not specified a
+	// default factor is used.
+	RestartRandomizationFactor float64
+
+	// IdleTimeout is the amount of time without any runtime calls after which the runtime process
+	// is stopped. The runtime is started again on the next call. As the runtime remains available
+	// to consumers, idle stops emit stopped events with the Idle flag set. Zero disables idle
+	// stops.
+	IdleTimeout time.Duration
+
+	// StderrTailLines is the number of last lines of runtime stderr output that are attached to
+	// events emitted when the runtime fails to start or terminates unexpectedly. In case it is
+	// not specified a default number of lines is used.
+	StderrTailLines int
+
+	// AllowedBindROPrefixes is a list of host path prefixes that runtimes may request to be
+	// bound read-only into the sandbox via host.Config.BindRO. In case it is empty, no additional
+	// bind mounts are allowed.
+	AllowedBindROPrefixes []string
+
+	// WarmStandby keeps a second, fully initialized runtime process ready, which is swapped in
+	// as soon as the active process terminates, without waiting for a full initialization. A new
+	// standby process is then started in the background.
+	//
+	// It cannot be used together with PersistentRuntimeDir, as both processes would share the
+	// same runtime directory.
+	WarmStandby bool
+
 	// InsecureNoSandbox disables the sandbox and runs the runtime binary directly.
 	InsecureNoSandbox bool
 }
 
+// getSandboxConfig generates the sandbox configuration for the given runtime, including any extra
+// environment variables and read-only bind mounts requested by the runtime configuration, which
+// must not override the ones configured by GetSandboxConfig.
+func (cfg *Config) getSandboxConfig(rtCfg host.Config, socketPath, runtimeDir string) (process.Config, error) {
+	pCfg, err := cfg.GetSandboxConfig(rtCfg, socketPath, runtimeDir)
+	if err != nil {
+		return process.Config{}, err
+	}
+
+	if len(rtCfg.ExtraEnv) > 0 {
+		env := make(map[string]string, len(pCfg.Env)+len(rtCfg.ExtraEnv))
+		for k, v := range pCfg.Env {
+			env[k] = v
+		}
+		for k, v := range rtCfg.ExtraEnv {
+			if _, ok := env[k]; ok || k == envWorkerHost {
+				return process.Config{}, fmt.Errorf("environment variable %s is reserved", k)
+			}
+			env[k] = v
+		}
+		pCfg.Env = env
+	}
+
+	bindRO, err := getBindRO(rtCfg.BindRO, cfg.AllowedBindROPrefixes)
+	if err != nil {
+		return process.Config{}, err
+	}
+	if len(bindRO) > 0 {
+		mountPoints := make(map[string]struct{})
+		for _, binds := range []map[string]string{pCfg.BindRW, pCfg.BindRO, pCfg.BindDev} {
+			for _, mountPoint := range binds {
+				mountPoints[filepath.Clean(mountPoint)] = struct{}{}
+			}
+		}
+		for mountPoint := range pCfg.BindData {
+			mountPoints[filepath.Clean(mountPoint)] = struct{}{}
+		}
+
+		merged := make(map[string]string, len(pCfg.BindRO)+len(bindRO))
+		for hostPath, mountPoint := range pCfg.BindRO {
+			merged[hostPath] = mountPoint
+		}
+		for hostPath, mountPoint := range bindRO {
+			if _, ok := mountPoints[mountPoint]; ok {
+				return process.Config{}, fmt.Errorf("bind mount point %s is reserved", mountPoint)
+			}
+			if _, ok := merged[hostPath]; ok {
+				return process.Config{}, fmt.Errorf("bind mount of %s is reserved", hostPath)
+			}
+			merged[hostPath] = mountPoint
+		}
+		pCfg.BindRO = merged
+	}
+
+	return pCfg, nil
+}
+
+// newRestartBackOff creates a new backoff used for runtime restarts.
+func (cfg *Config) newRestartBackOff() *backoff.ExponentialBackOff {
+	boff := cmnBackoff.NewExponentialBackOff()
+	boff.InitialInterval = cfg.RestartInitialInterval
+	boff.MaxInterval = cfg.RestartMaxInterval
+	boff.RandomizationFactor = cfg.RestartRandomizationFactor
+	boff.Reset()
+	return boff
+}
+
 // HostInitializerParams contains parameters for the HostInitializer function.
 type HostInitializerParams struct {
 	Runtime    host.Runtime
@@ -81,14 +262,28 @@ func (p *provisioner) NewRuntime(cfg host.Config) (host.Runtime, error) {
 	id := cfg.Bundle.Manifest.ID
 
 	r := &sandboxedRuntime{
-		cfg:                         p.cfg,
-		rtCfg:                       cfg,
-		id:                          id,
-		stopCh:                      make(chan struct{}),
-		ctrlCh:                      make(chan interface{}, ctrlChannelBufferSize),
-		notifier:                    pubsub.NewBroker(false),
-		notifyUpdateCapabilityTEECh: make(chan struct{}, 1),
-		logger:                      p.cfg.Logger.With("runtime_id", id),
+		cfg:      p.cfg,
+		rtCfg:    cfg,
+		id:       id,
+		stopCh:   make(chan struct{}),
+		ctrlCh:   make(chan interface{}, ctrlChannelBufferSize),
+		wakeCh:   make(chan struct{}, 1),
+		notifier: pubsub.NewBroker(false),
+		logger:   p.cfg.Logger.With("runtime_id", id),
+	}
+	r.stderr = r.newStderrTail()
+
+	if p.cfg.MaxConcurrentRequests > 0 {
+		r.callSem = make(chan struct{}, p.cfg.MaxConcurrentRequests)
+	}
+
+	// Load the in-memory runtime binary, if any, so that it can be used on each start.
+	if cfg.Bundle.Binary != nil {
+		binary, err := io.ReadAll(cfg.Bundle.Binary)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read runtime binary: %w", err)
+		}
+		r.binary = binary
 	}
 
 	return r, nil
@@ -99,6 +294,17 @@ func (p *provisioner) Name() string {
 	return "sandbox"
 }
 
+// versionMismatchError is the error returned when the runtime reports a version that differs
+// from the one configured in the bundle.
+type versionMismatchError struct {
+	reported version.Version
+	expected version.Version
+}
+
+func (e *versionMismatchError) Error() string {
+	return fmt.Sprintf("version mismatch (runtime reported: %s bundle: %s)", e.reported, e.expected)
+}
+
 // abortRequest is a request to the runtime manager goroutine to abort the runtime.
 // In case of failures or if force flag is set, the runtime is restarted.
 type abortRequest struct {
@@ -106,24 +312,89 @@ type abortRequest struct {
 	force bool
 }
 
+// restartRequest is a request to the runtime manager goroutine to cleanly stop and respawn the
+// runtime.
+type restartRequest struct {
+	ch chan<- error
+}
+
+// spawnedProcess is a runtime process that has been spawned and initialized, but is not
+// necessarily the active process.
+type spawnedProcess struct {
+	process   process.Process
+	conn      protocol.Connection
+	ev        *host.StartedEvent
+	rtVersion *version.Version
+	startTime time.Time
+
+	stderr                      *stderrTail
+	notifyUpdateCapabilityTEECh chan struct{}
+
+	// capabilityTEE is the latest CapabilityTEE of the process, protected by the runtime lock.
+	capabilityTEE *node.CapabilityTEE
+}
+
+// kill kills the process and waits for it to terminate for at most the given amount of time.
+func (sp *spawnedProcess) kill(timeout time.Duration) error {
+	sp.conn.Close()
+	sp.process.Kill()
+
+	select {
+	case <-sp.process.Wait():
+		return nil
+	case <-time.After(timeout):
+		return errRuntimeNotStopped
+	}
+}
+
+// processRuntime is the view of the runtime handed to the host initializer of a single runtime
+// process. Events emitted through it only affect the runtime while the process is active, so that
+// a standby process cannot interfere with the active one.
+type processRuntime struct {
+	*sandboxedRuntime
+
+	sp *spawnedProcess
+}
+
+// Implements host.RuntimeEventEmitter.
+func (pr *processRuntime) EmitEvent(ev *host.Event) {
+	pr.emitProcessEvent(pr.sp, ev)
+}
+
 type sandboxedRuntime struct {
 	sync.RWMutex
 
-	cfg   Config
-	rtCfg host.Config
-	id    common.Namespace
+	cfg    Config
+	rtCfg  host.Config
+	id     common.Namespace
+	binary []byte
 
 	startOnce sync.Once
 	stopOnce  sync.Once
 	stopCh    chan struct{}
 	ctrlCh    chan interface{}
+	wakeCh    chan struct{}
 
 	process  process.Process
 	conn     protocol.Connection
+	active   *spawnedProcess
+	callSem  chan struct{}
 	notifier *pubsub.Broker
 
-	notifyUpdateCapabilityTEECh chan struct{}
-	capabilityTEE               *node.CapabilityTEE
+	pid          int
+	startTime    time.Time
+	restartCount uint64
+
+	activeCalls  int
+	lastCallTime time.Time
+	// idle is true while the runtime process is stopped due to being idle.
+	idle bool
+	// standbyReady is true while a warm standby process is ready to be swapped in.
+	standbyReady bool
+
+	stderr *stderrTail
+
+	capabilityTEE *node.CapabilityTEE
 
 	rtVersion *version.Version
 
@@ -140,7 +411,7 @@ func (r *sandboxedRuntime) GetActiveVersion() (*version.Version, error) {
 	r.RLock()
 	defer r.RUnlock()
 
-	if r.conn == nil {
+	if r.conn == nil && !r.idle {
 		return nil, errRuntimeNotReady
 	}
 	return r.rtVersion, nil
@@ -148,6 +419,12 @@ func (r *sandboxedRuntime) GetActiveVersion() (*version.Version, error) {
 
 // Implements host.Runtime.
 func (r *sandboxedRuntime) GetInfo(ctx context.Context) (*protocol.RuntimeInfoResponse, error) {
+	// Track calls so that idle runtimes are not stopped again before they are used.
+	if r.tracksCalls() {
+		r.callStarted()
+		defer r.callFinished()
+	}
+
 	conn, err := r.getConnection(ctx)
 	if err != nil {
 		return nil, err
@@ -161,14 +438,36 @@ func (r *sandboxedRuntime) GetCapabilityTEE() (*node.CapabilityTEE, error) {
 	r.RLock()
 	defer r.RUnlock()
 
-	if r.conn == nil {
+	if r.conn == nil && !r.idle {
 		return nil, errRuntimeNotReady
 	}
 	return r.capabilityTEE, nil
 }
 
+// Implements host.Runtime.
+func (r *sandboxedRuntime) GetProcessInfo() (*host.ProcessInfo, error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	if r.conn == nil {
+		return nil, errRuntimeNotReady
+	}
+	return &host.ProcessInfo{
+		PID:          r.pid,
+		StartTime:    r.startTime,
+		RestartCount: r.restartCount,
+	}, nil
+}
+
 // Implements host.Runtime.
 func (r *sandboxedRuntime) Call(ctx context.Context, body *protocol.Body) (*protocol.Body, error) {
+	// Track calls so that idle runtimes can be stopped and busy runtimes are not health checked,
+	// if configured.
+	if r.tracksCalls() {
+		r.callStarted()
+		defer r.callFinished()
+	}
+
 	conn, err := r.getConnection(ctx)
 	if err != nil {
 		return nil, err
@@ -178,6 +477,16 @@ func (r *sandboxedRuntime) Call(ctx context.Context, body *protocol.Body) (*prot
 	// deadlock in case the runtime makes a call that acquires the cross node lock and at the same
 	// time SetVersion is being called to update the version with the cross node lock acquired.
 
+	// Limit the number of concurrent requests, if configured.
+	if r.callSem != nil {
+		select {
+		case r.callSem <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		defer func() { <-r.callSem }()
+	}
+
 	return conn.Call(ctx, body)
 }
 
@@ -188,12 +497,18 @@ func (r *sandboxedRuntime) getConnection(ctx context.Context) (protocol.Connecti
 		defer r.RUnlock()
 
 		if r.conn == nil {
+			// Make sure the runtime is started in case it has been stopped due to being idle.
+			select {
+			case r.wakeCh <- struct{}{}:
+			default:
+			}
 			return errRuntimeNotReady
 		}
 		conn = r.conn
 
 		return nil
 	}
+
 	// Retry call in case the runtime is not yet ready.
 	err := backoff.Retry(getConnFn, backoff.WithContext(cmnBackoff.NewExponentialBackOff(), ctx))
 	if err != nil {
@@ -203,10 +518,64 @@ func (r *sandboxedRuntime) getConnection(ctx context.Context) (protocol.Connecti
 	return conn, nil
 }
 
+// tracksCalls returns true iff runtime calls need to be tracked.
+func (r *sandboxedRuntime) tracksCalls() bool {
+	return r.cfg.IdleTimeout > 0 || r.cfg.HealthCheckInterval > 0
+}
+
+func (r *sandboxedRuntime) callStarted() {
+	r.Lock()
+	defer r.Unlock()
+
+	r.activeCalls++
+	r.lastCallTime = time.Now()
+}
+
+func (r *sandboxedRuntime) callFinished() {
+	r.Lock()
+	defer r.Unlock()
+
+	r.activeCalls--
+	r.lastCallTime = time.Now()
+}
+
+// hasActiveCalls returns true iff there are any runtime calls in flight.
+func (r *sandboxedRuntime) hasActiveCalls() bool {
+	r.RLock()
+	defer r.RUnlock()
+
+	return r.activeCalls > 0
+}
+
+// idleTime returns the amount of time since the last runtime call or since the runtime has been
+// started, whichever is later.
+func (r *sandboxedRuntime) idleTime() time.Duration {
+	r.RLock()
+	defer r.RUnlock()
+
+	if r.activeCalls > 0 {
+		return 0
+	}
+	last := r.lastCallTime
+	if last.Before(r.startTime) {
+		last = r.startTime
+	}
+	return time.Since(last)
+}
+
 // Implements host.Runtime.
 func (r *sandboxedRuntime) UpdateCapabilityTEE() {
+	r.RLock()
+	sp := r.active
+	r.RUnlock()
+
+	if sp == nil {
+		// A newly started process will generate a fresh CapabilityTEE anyway.
+		return
+	}
+
 	select {
-	case r.notifyUpdateCapabilityTEECh <- struct{}{}:
+	case sp.notifyUpdateCapabilityTEECh <- struct{}{}:
 	default:
 	}
 }
@@ -220,6 +589,39 @@ func (r *sandboxedRuntime) WatchEvents() (<-chan *host.Event, pubsub.ClosableSub
 	return typedCh, sub
 }
 
+// Implements host.Runtime.
+func (r *sandboxedRuntime) WatchEventsFiltered(kinds ...host.EventKind) (<-chan *host.Event, pubsub.ClosableSubscription) {
+	ch, sub := r.WatchEvents()
+	return host.FilterEvents(ch, sub, kinds...)
+}
+
+// WaitForUnavailable waits for the runtime to either stop or fail to start. In case the runtime
+// is currently not running, the method returns immediately. Runtimes stopped due to being idle
+// remain available.
+func (r *sandboxedRuntime) WaitForUnavailable(ctx context.Context) error {
+	// Subscribe before checking the current state so no stop events can be missed.
+	evCh, evSub := r.WatchEvents()
+	defer evSub.Close()
+
+	r.RLock()
+	running := r.conn != nil || r.idle
+	r.RUnlock()
+	if !running {
+		return nil
+	}
+
+	for {
+		select {
+		case ev := <-evCh:
+			if (ev.Stopped != nil && !ev.Stopped.Idle) || ev.FailedToStart != nil {
+				return nil
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
 // Implements host.Runtime.
 func (r *sandboxedRuntime) Start() {
 	r.startOnce.Do(func() {
@@ -229,10 +631,20 @@ func (r *sandboxedRuntime) Start() {
 
 // Implements host.Runtime.
 func (r *sandboxedRuntime) Abort(ctx context.Context, force bool) error {
+	// Do not queue requests in case the manager goroutine is terminating as they would never get
+	// processed.
+	select {
+	case <-r.stopCh:
+		return errRuntimeStopped
+	default:
+	}
+
 	// Send internal request to the manager goroutine.
 	ch := make(chan error, 1)
 	select {
 	case r.ctrlCh <- &abortRequest{ch: ch, force: force}:
+	case <-r.stopCh:
+		return errRuntimeStopped
 	case <-ctx.Done():
 		return ctx.Err()
 	}
@@ -241,11 +653,67 @@ func (r *sandboxedRuntime) Abort(ctx context.Context, force bool) error {
 	select {
 	case err := <-ch:
 		return err
+	case <-r.stopCh:
+		return errRuntimeStopped
 	case <-ctx.Done():
 		return ctx.Err()
 	}
 }
 
+// Implements host.Runtime.
+func (r *sandboxedRuntime) Restart(ctx context.Context) error {
+	// Do not queue requests in case the manager goroutine is terminating as they would never get
+	// processed.
+	select {
+	case <-r.stopCh:
+		return errRuntimeStopped
+	default:
+	}
+
+	// Subscribe before requesting the restart so the started event cannot be missed.
+	evCh, evSub := r.WatchEvents()
+	defer evSub.Close()
+
+	// Send internal request to the manager goroutine.
+	ch := make(chan error, 1)
+	select {
+	case r.ctrlCh <- &restartRequest{ch: ch}:
+	case <-r.stopCh:
+		return errRuntimeStopped
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	// Wait for the manager goroutine to stop the runtime.
+	select {
+	case err := <-ch:
+		if err != nil {
+			return err
+		}
+	case <-r.stopCh:
+		return errRuntimeStopped
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	// Wait for the runtime to be started again.
+	for {
+		select {
+		case ev := <-evCh:
+			switch {
+			case ev.Started != nil:
+				return nil
+			case ev.FailedToStart != nil:
+				return ev.FailedToStart.Error
+			}
+		case <-r.stopCh:
+			return errRuntimeStopped
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
 // Implements host.Runtime.
 func (r *sandboxedRuntime) Stop() {
 	r.stopOnce.Do(func() {
@@ -255,21 +723,137 @@ func (r *sandboxedRuntime) Stop() {
 
 // Implements host.EmitEvent.
 func (r *sandboxedRuntime) EmitEvent(ev *host.Event) {
+	// Update runtime's CapabilityTEE in case this is an update event and record what changed.
+	if ue := ev.Updated; ue != nil {
+		r.Lock()
+		ue.Changed = host.DiffCapabilityTEE(r.capabilityTEE, ue.CapabilityTEE)
+		r.capabilityTEE = ue.CapabilityTEE
+		r.Unlock()
+	}
+
+	r.notifier.Broadcast(ev)
+}
+
+// emitProcessEvent emits an event on behalf of the given runtime process. Events of processes
+// that are not active are not broadcast, but the latest CapabilityTEE is recorded so that it can
+// be used once the process gets activated.
+func (r *sandboxedRuntime) emitProcessEvent(sp *spawnedProcess, ev *host.Event) {
+	r.Lock()
+	if r.active != sp {
+		if ue := ev.Updated; ue != nil {
+			sp.capabilityTEE = ue.CapabilityTEE
+		}
+		r.Unlock()
+		return
+	}
+	if ue := ev.Updated; ue != nil {
+		ue.Changed = host.DiffCapabilityTEE(r.capabilityTEE, ue.CapabilityTEE)
+		r.capabilityTEE = ue.CapabilityTEE
+		sp.capabilityTEE = ue.CapabilityTEE
+	}
+	r.Unlock()
+
 	r.notifier.Broadcast(ev)
 }
 
-func (r *sandboxedRuntime) startProcess() (err error) {
+func (r *sandboxedRuntime) persistentRuntimeDir() string {
+	return filepath.Join(r.cfg.PersistentRuntimeDir, r.id.String())
+}
+
+// materializeBinary writes the in-memory runtime binary into the given directory and returns
+// a runtime configuration that refers to it.
+func (r *sandboxedRuntime) materializeBinary(dir string) (host.Config, error) {
+	path := filepath.Join(dir, "runtime")
+	if err := os.WriteFile(path, r.binary, 0o700); err != nil { // nolint: gosec
+		return host.Config{}, fmt.Errorf("failed to materialize runtime binary: %w", err)
+	}
+
+	bnd := *r.rtCfg.Bundle
+	bnd.Path = path
+	bnd.Binary = nil
+
+	rtCfg := r.rtCfg
+	rtCfg.Bundle = &bnd
+	return rtCfg, nil
+}
+
+func (r *sandboxedRuntime) newStderrTail() *stderrTail {
+	return &stderrTail{maxLines: r.cfg.StderrTailLines}
+}
+
+func (r *sandboxedRuntime) newSpawnedProcess() *spawnedProcess {
+	return &spawnedProcess{
+		stderr:                      r.newStderrTail(),
+		notifyUpdateCapabilityTEECh: make(chan struct{}, 1),
+	}
+}
+
+func (r *sandboxedRuntime) startProcess(ctx context.Context) error {
+	sp := r.newSpawnedProcess()
+
+	// Report the stderr output of the process being started in case it fails.
+	r.stderr = sp.stderr
+
+	if err := r.spawnProcess(ctx, sp); err != nil {
+		return err
+	}
+	r.activateProcess(sp)
+	return nil
+}
+
+// activateProcess makes the given spawned process the active runtime process.
+func (r *sandboxedRuntime) activateProcess(sp *spawnedProcess) {
+	r.process = sp.process
+	r.stderr = sp.stderr
+	r.Lock()
+	r.active = sp
+	r.conn = sp.conn
+	r.idle = false
+	r.capabilityTEE = sp.capabilityTEE
+	r.rtVersion = sp.rtVersion
+	r.pid = sp.process.GetPID()
+	r.startTime = sp.startTime
+	// Make sure the started event includes any CapabilityTEE updates made while the process was
+	// not active.
+	sp.ev.CapabilityTEE = sp.capabilityTEE
+	r.Unlock()
+
+	// Notify subscribers that a runtime has been started.
+	r.notifier.Broadcast(&host.Event{Started: sp.ev})
+}
+
+// spawnProcess spawns and initializes the given runtime process without making it active. The
+// given context should be cancelled when the runtime is stopped.
+func (r *sandboxedRuntime) spawnProcess(ctx context.Context, sp *spawnedProcess) error {
 	// Create a temporary directory.
-	runtimeDir, err := os.MkdirTemp("", "oasis-runtime")
+	tmpDir, err := os.MkdirTemp("", "oasis-runtime")
 	if err != nil {
 		return fmt.Errorf("failed to create temporary directory: %w", err)
 	}
 	// We can remove the worker directory after the worker has been started as it
 	// has been mounted into the sandbox and is no longer needed.
-	defer os.RemoveAll(runtimeDir)
+	defer os.RemoveAll(tmpDir)
+
+	// Materialize the in-memory runtime binary, if any.
+	rtCfg := r.rtCfg
+	if r.binary != nil {
+		if rtCfg, err = r.materializeBinary(tmpDir); err != nil {
+			return err
+		}
+	}
+
+	// Use a persistent runtime directory if configured.
+	runtimeDir := tmpDir
+	if r.cfg.PersistentRuntimeDir != "" {
+		runtimeDir = r.persistentRuntimeDir()
+		if err = os.MkdirAll(runtimeDir, 0o700); err != nil {
+			return fmt.Errorf("failed to create persistent runtime directory: %w", err)
+		}
+	}
 
-	// Create unix socket.
-	hostSocket := filepath.Join(runtimeDir, "host.sock")
+	// Create unix socket. The socket is always created in the temporary directory to keep its
+	// path short.
+	hostSocket := filepath.Join(tmpDir, "host.sock")
 	listener, err := net.ListenUnix("unix", &net.UnixAddr{Name: hostSocket})
 	if err != nil {
 		return fmt.Errorf("failed to create host socket: %w", err)
@@ -294,10 +878,11 @@ func (r *sandboxedRuntime) startProcess() (err error) {
 		// No sandbox.
 		r.logger.Warn("starting an UNSANDBOXED runtime")
 
-		cfg, cErr := r.cfg.GetSandboxConfig(r.rtCfg, hostSocket, runtimeDir)
+		cfg, cErr := r.cfg.getSandboxConfig(rtCfg, hostSocket, runtimeDir)
 		if cErr != nil {
 			return fmt.Errorf("failed to configure process: %w", cErr)
 		}
+		captureStderr(&cfg, sp.stderr)
 
 		p, err = process.NewNaked(cfg)
 		if err != nil {
@@ -305,31 +890,45 @@ func (r *sandboxedRuntime) startProcess() (err error) {
 		}
 	case false:
 		// With sandbox.
-		cfg, cErr := r.cfg.GetSandboxConfig(r.rtCfg, bindHostSocketPath, runtimeDir)
+		cfg, cErr := r.cfg.getSandboxConfig(rtCfg, bindHostSocketPath, runtimeDir)
 		if cErr != nil {
 			return fmt.Errorf("failed to configure sandbox: %w", cErr)
 		}
+		captureStderr(&cfg, sp.stderr)
 
 		if cfg.BindRW == nil {
 			cfg.BindRW = make(map[string]string)
 		}
 		cfg.BindRW[hostSocket] = bindHostSocketPath
 
+		// Apply resource limits unless they have been configured explicitly.
+		if cfg.CPUQuota == 0 {
+			cfg.CPUQuota = r.cfg.CPUQuota
+		}
+		if cfg.MemoryLimitBytes == 0 {
+			cfg.MemoryLimitBytes = r.cfg.MemoryLimitBytes
+		}
+		if cfg.CgroupParent == "" {
+			cfg.CgroupParent = r.cfg.CgroupParent
+		}
+
 		p, err = process.NewBubbleWrap(cfg)
 		if err != nil {
 			return fmt.Errorf("failed to spawn sandbox: %w", err)
 		}
 	}
 
+	startTime := time.Now()
+
 	// Wait for the runtime to connect.
 	r.logger.Info("waiting for runtime to connect",
 		"pid", p.GetPID(),
 	)
 
 	// Spawn goroutine that waits for a connection to be established.
-	connCh := make(chan interface{})
+	connCh := make(chan interface{}, 1)
 	go func() {
-		lerr := listener.SetDeadline(time.Now().Add(runtimeConnectTimeout))
+		lerr := listener.SetDeadline(time.Now().Add(r.cfg.RuntimeConnectTimeout))
 		if lerr != nil {
 			connCh <- lerr
 			return
@@ -364,6 +963,9 @@ func (r *sandboxedRuntime) startProcess() (err error) {
 		)
 
 		return fmt.Errorf("terminated while waiting for runtime to connect")
+	case <-ctx.Done():
+		// Runtime has been stopped before a connection was accepted.
+		return ctx.Err()
 	}
 
 	// Initialize the connection.
@@ -382,17 +984,6 @@ func (r *sandboxedRuntime) startProcess() (err error) {
 		}
 	}()
 
-	// Create a context that gets cancelled if runtime is stopped.
-	ctx, cancel := context.WithCancel(context.Background())
-	go func() {
-		select {
-		case <-ctx.Done():
-		case <-r.stopCh:
-			cancel()
-		}
-	}()
-	defer cancel()
-
 	// Populate the runtime-specific parts of host information.
 	hi := r.cfg.HostInfo.Clone()
 	hi.LocalConfig = r.rtCfg.LocalConfig
@@ -407,15 +998,15 @@ func (r *sandboxedRuntime) startProcess() (err error) {
 
 	// Make sure the version matches what is configured in the bundle.
 	if bndVersion := r.rtCfg.Bundle.Manifest.Version; *rtVersion != bndVersion {
-		return fmt.Errorf("version mismatch (runtime reported: %s bundle: %s)", *rtVersion, bndVersion)
+		return &versionMismatchError{reported: *rtVersion, expected: bndVersion}
 	}
 
 	hp := &HostInitializerParams{
-		Runtime:                   r,
+		Runtime:                   &processRuntime{sandboxedRuntime: r, sp: sp},
 		Version:                   *rtVersion,
 		Process:                   p,
 		Connection:                pc,
-		NotifyUpdateCapabilityTEE: r.notifyUpdateCapabilityTEECh,
+		NotifyUpdateCapabilityTEE: sp.notifyUpdateCapabilityTEECh,
 	}
 
 	// Perform configuration-specific host initialization.
@@ -427,24 +1018,37 @@ func (r *sandboxedRuntime) startProcess() (err error) {
 	}
 
 	ok = true
-	r.process = p
+	sp.process = p
+	sp.conn = pc
+	sp.ev = ev
+	sp.rtVersion = rtVersion
+	sp.startTime = startTime
+
 	r.Lock()
-	r.conn = pc
-	r.capabilityTEE = ev.CapabilityTEE
-	r.rtVersion = rtVersion
+	if sp.capabilityTEE == nil {
+		sp.capabilityTEE = ev.CapabilityTEE
+	}
 	r.Unlock()
 
-	// Notify subscribers that a runtime has been started.
-	r.notifier.Broadcast(&host.Event{Started: ev})
-
 	return nil
 }
 
+// captureStderr makes sure the last lines of the process stderr output are captured in the given
+// tail, in addition to being written to the configured stderr writer.
+func captureStderr(cfg *process.Config, tail *stderrTail) {
+	switch cfg.Stderr {
+	case nil:
+		cfg.Stderr = tail
+	default:
+		cfg.Stderr = io.MultiWriter(cfg.Stderr, tail)
+	}
+}
+
 func (r *sandboxedRuntime) handleAbortRequest(rq *abortRequest) error {
 	r.logger.Warn("interrupting runtime")
 
 	// First attempt to gracefully interrupt the runtime by sending a request.
-	ctx, cancel := context.WithTimeout(context.Background(), runtimeInterruptTimeout)
+	ctx, cancel := context.WithTimeout(context.Background(), r.cfg.RuntimeInterruptTimeout)
 	defer cancel()
 
 	response, err := r.conn.Call(ctx, &protocol.Body{RuntimeAbortRequest: &protocol.Empty{}})
@@ -465,29 +1069,215 @@ func (r *sandboxedRuntime) handleAbortRequest(rq *abortRequest) error {
 	case <-r.process.Wait():
 	case <-r.stopCh:
 		return context.Canceled
+	case <-time.After(r.cfg.RuntimeKillTimeout):
+		r.logger.Error("runtime did not terminate after being killed",
+			"pid", r.process.GetPID(),
+			"timeout", r.cfg.RuntimeKillTimeout,
+		)
+		return errRuntimeNotStopped
 	}
 
 	r.logger.Warn("runtime terminated due to restart request")
 
 	// Remove the process so it will be respanwed (it would be respawned either way, but with an
 	// additional "unexpected termination" message).
+	r.removeProcess(&host.StoppedEvent{})
+
+	return nil
+}
+
+func (r *sandboxedRuntime) handleRestartRequest() error {
+	r.logger.Info("stopping runtime due to restart request")
+
+	if err := r.terminateProcess(); err != nil {
+		return err
+	}
+
+	// Remove the process so it will be respawned.
+	r.removeProcess(&host.StoppedEvent{})
+
+	return nil
+}
+
+// terminateProcess asks the runtime to exit gracefully, killing it after the grace period.
+func (r *sandboxedRuntime) terminateProcess() error {
+	// Ask the runtime to exit gracefully, killing it after the grace period.
+	r.conn.Close()
+	r.process.Terminate(r.cfg.TerminationGracePeriod)
+
+	select {
+	case <-r.process.Wait():
+	case <-r.stopCh:
+		return context.Canceled
+	case <-time.After(r.cfg.RuntimeKillTimeout):
+		r.logger.Error("runtime did not terminate after being killed",
+			"pid", r.process.GetPID(),
+			"timeout", r.cfg.RuntimeKillTimeout,
+		)
+		return errRuntimeNotStopped
+	}
+
+	return nil
+}
+
+// removeProcess clears the state of a terminated runtime process and notifies subscribers that
+// the runtime has stopped.
+func (r *sandboxedRuntime) removeProcess(ev *host.StoppedEvent) {
 	r.conn.Close()
 	r.process = nil
 	r.Lock()
+	r.active = nil
 	r.conn = nil
+	r.idle = false
 	r.capabilityTEE = nil
 	r.rtVersion = nil
 	r.Unlock()
 
 	// Notify subscribers that the runtime has stopped.
-	r.notifier.Broadcast(&host.Event{Stopped: &host.StoppedEvent{}})
+	r.notifier.Broadcast(&host.Event{Stopped: ev})
+}
 
-	return nil
+// suspendProcess clears the state of a runtime process that has been stopped due to being idle
+// and notifies subscribers that the runtime process has stopped.
+//
+// Unlike removeProcess, the runtime version and CapabilityTEE are retained and the stopped event
+// has the Idle flag set, so that consumers keep treating the runtime as available and keep
+// issuing the calls that start it again. A started event is emitted once that happens.
+func (r *sandboxedRuntime) suspendProcess() {
+	r.conn.Close()
+	r.process = nil
+	r.Lock()
+	r.active = nil
+	r.conn = nil
+	r.idle = true
+	r.Unlock()
+
+	r.notifier.Broadcast(&host.Event{Stopped: &host.StoppedEvent{Idle: true}})
+}
+
+// rejectRequest responds to a request to the runtime manager goroutine with the given error.
+func (r *sandboxedRuntime) rejectRequest(grq interface{}, err error) {
+	switch rq := grq.(type) {
+	case *abortRequest:
+		rq.ch <- err
+		close(rq.ch)
+	case *restartRequest:
+		rq.ch <- err
+		close(rq.ch)
+	default:
+		r.logger.Error("received unknown request type",
+			"request_type", fmt.Sprintf("%T", rq),
+		)
+	}
+}
+
+// healthCheckResult is the result of a runtime health check.
+type healthCheckResult struct {
+	process process.Process
+	err     error
+}
+
+// healthCheck pings the given runtime process and reports the result on the given channel.
+func (r *sandboxedRuntime) healthCheck(p process.Process, conn protocol.Connection, ch chan<- *healthCheckResult) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.cfg.HealthCheckTimeout)
+	defer cancel()
+
+	_, err := conn.Call(ctx, &protocol.Body{RuntimePingRequest: &protocol.Empty{}})
+	ch <- &healthCheckResult{process: p, err: err}
 }
 
 func (r *sandboxedRuntime) manager() {
 	var ticker *backoff.Ticker
 
+	// Create a context that gets cancelled if runtime is stopped.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-r.stopCh:
+			cancel()
+		}
+	}()
+
+	// Warm standby state.
+	var (
+		standby        *spawnedProcess
+		standbyPending bool
+		standbyCh      = make(chan *spawnedProcess, 1)
+		standbyWaitCh  <-chan struct{}
+		standbyRetryCh <-chan time.Time
+	)
+	setStandby := func(sp *spawnedProcess) {
+		standby = sp
+		standbyWaitCh = nil
+		if sp != nil {
+			standbyWaitCh = sp.process.Wait()
+		}
+
+		r.Lock()
+		r.standbyReady = sp != nil
+		r.Unlock()
+	}
+	startStandby := func() {
+		if !r.cfg.WarmStandby || standby != nil || standbyPending {
+			return
+		}
+		standbyPending = true
+		standbyRetryCh = nil
+
+		go func() {
+			sp := r.newSpawnedProcess()
+			if err := r.spawnProcess(ctx, sp); err != nil {
+				r.logger.Warn("failed to start standby runtime",
+					"err", err,
+				)
+				sp = nil
+			}
+			standbyCh <- sp
+		}()
+	}
+	killStandby := func(sp *spawnedProcess) {
+		if err := sp.kill(r.cfg.RuntimeKillTimeout); err != nil {
+			r.logger.Error("standby runtime did not terminate after being killed",
+				"pid", sp.process.GetPID(),
+				"timeout", r.cfg.RuntimeKillTimeout,
+			)
+		}
+	}
+	stopStandby := func() {
+		if standby == nil {
+			return
+		}
+		killStandby(standby)
+		setStandby(nil)
+	}
+	takeStandby := func() *spawnedProcess {
+		if standbyPending {
+			select {
+			case sp := <-standbyCh:
+				standbyPending = false
+				setStandby(sp)
+			default:
+			}
+		}
+		if standby == nil {
+			return nil
+		}
+
+		sp := standby
+		setStandby(nil)
+
+		select {
+		case <-sp.process.Wait():
+			// The standby has terminated as well.
+			sp.conn.Close()
+			return nil
+		default:
+			return sp
+		}
+	}
+
 	defer func() {
 		r.logger.Warn("terminating runtime")
 
@@ -495,67 +1285,228 @@ func (r *sandboxedRuntime) manager() {
 			ticker.Stop()
 			ticker = nil
 		}
+		stopStandby()
+		if standbyPending {
+			if sp := <-standbyCh; sp != nil {
+				killStandby(sp)
+			}
+		}
 		if r.process != nil {
+			// Ask the runtime to exit gracefully, killing it after the grace period.
 			r.conn.Close()
-			r.process.Kill()
-			<-r.process.Wait()
+			r.process.Terminate(r.cfg.TerminationGracePeriod)
+
+			select {
+			case <-r.process.Wait():
+			case <-time.After(r.cfg.RuntimeKillTimeout):
+				r.logger.Error("runtime did not terminate after being killed",
+					"pid", r.process.GetPID(),
+					"timeout", r.cfg.RuntimeKillTimeout,
+				)
+			}
 			r.process = nil
 
 			r.Lock()
+			r.active = nil
 			r.conn = nil
 			r.capabilityTEE = nil
 			r.Unlock()
 		}
+		r.Lock()
+		r.idle = false
+		r.Unlock()
+
+		// Remove the persistent runtime directory, if any.
+		if r.cfg.PersistentRuntimeDir != "" {
+			if err := os.RemoveAll(r.persistentRuntimeDir()); err != nil {
+				r.logger.Error("failed to remove persistent runtime directory",
+					"err", err,
+				)
+			}
+		}
 
 		// Notify subscribers that the runtime has stopped.
 		r.notifier.Broadcast(&host.Event{Stopped: &host.StoppedEvent{}})
 	}()
 
-	// Subscribe to own events to make sure the cached CapabilityTEE remains up-to-date.
-	evCh, evSub := r.WatchEvents()
-	defer evSub.Close()
+	// Periodically check that the runtime is responsive, if configured. Health checks are
+	// performed in the background so that they do not block the handling of other requests.
+	var (
+		healthCheckCh      <-chan time.Time
+		healthCheckPending bool
+		healthCheckResCh   = make(chan *healthCheckResult, 1)
+	)
+	if r.cfg.HealthCheckInterval > 0 {
+		healthCheckTicker := time.NewTicker(r.cfg.HealthCheckInterval)
+		defer healthCheckTicker.Stop()
+		healthCheckCh = healthCheckTicker.C
+	}
 
-	var attempt int
+	var (
+		attempt             int
+		restart             bool
+		idle                bool
+		permanentErr        error
+		healthCheckFailures int
+		resetTickerCh       <-chan time.Time
+		idleCh              <-chan time.Time
+	)
 	for {
 		// Make sure to restart the process if terminated.
 		if r.process == nil {
-			firstTickCh := make(chan struct{}, 1)
-			if ticker == nil {
-				// Initialize a ticker for restarting the process. We use a separate channel
-				// to restart the process immediately on the first run, as we don't want to wait
-				// for the first tick.
-				ticker = backoff.NewTicker(cmnBackoff.NewExponentialBackOff())
-				firstTickCh <- struct{}{}
-				attempt = 0
+			if permanentErr != nil {
+				// The runtime cannot be started, reject any requests until terminated.
+				select {
+				case <-r.stopCh:
+					r.logger.Warn("termination requested")
+					return
+				case grq := <-r.ctrlCh:
+					r.rejectRequest(grq, permanentErr)
+				}
+				continue
 			}
 
-			select {
-			case <-r.stopCh:
-				r.logger.Warn("termination requested")
-				return
-			case <-firstTickCh:
-			case <-ticker.C:
+			if idle {
+				// Wait for a call before starting the runtime again, unless one is already
+				// in progress.
+				r.RLock()
+				activeCalls := r.activeCalls
+				r.RUnlock()
+
+				if activeCalls == 0 {
+					select {
+					case <-r.stopCh:
+						r.logger.Warn("termination requested")
+						return
+					case <-r.wakeCh:
+					case grq := <-r.ctrlCh:
+						switch rq := grq.(type) {
+						case *abortRequest:
+							// There is nothing to abort while the runtime is idle.
+							rq.ch <- nil
+							close(rq.ch)
+							continue
+						case *restartRequest:
+							// The runtime is already stopped, so just start it again.
+							rq.ch <- nil
+							close(rq.ch)
+						default:
+							r.logger.Error("received unknown request type",
+								"request_type", fmt.Sprintf("%T", rq),
+							)
+							continue
+						}
+					}
+				}
+				idle = false
+			}
+
+			// Swap in the standby immediately if there is one ready.
+			sp := takeStandby()
+			if sp == nil {
+				firstTickCh := make(chan struct{}, 1)
+				if ticker == nil {
+					// Initialize a ticker for restarting the process. We use a separate channel
+					// to restart the process immediately on the first run, as we don't want to
+					// wait for the first tick.
+					ticker = backoff.NewTicker(r.cfg.newRestartBackOff())
+					firstTickCh <- struct{}{}
+					attempt = 0
+				}
+
+				select {
+				case <-r.stopCh:
+					r.logger.Warn("termination requested")
+					return
+				case <-firstTickCh:
+				case <-ticker.C:
+				}
 			}
 
 			attempt++
 			r.logger.Info("starting runtime",
 				"attempt", attempt,
 			)
+			currentAttempt.With(r.getMetricLabels()).Set(float64(attempt))
+			if restart {
+				restartCount.With(r.getMetricLabels()).Inc()
+				restart = false
 
-			if err := r.startProcess(); err != nil {
-				r.logger.Error("failed to start runtime",
-					"err", err,
+				r.Lock()
+				r.restartCount++
+				r.Unlock()
+			}
+
+			var err error
+			switch sp {
+			case nil:
+				err = r.startProcess(ctx)
+			default:
+				r.logger.Info("activating standby runtime",
+					"pid", sp.process.GetPID(),
 				)
+				r.activateProcess(sp)
+			}
+			if err != nil {
+				var vmErr *versionMismatchError
+				if errors.As(err, &vmErr) {
+					// Retrying is unlikely to help, so make sure to keep backing off.
+					r.logger.Error("runtime version mismatch",
+						"reported_version", vmErr.reported,
+						"expected_version", vmErr.expected,
+					)
+
+					r.notifier.Broadcast(&host.Event{
+						VersionMismatch: &host.VersionMismatchEvent{
+							Reported: vmErr.reported,
+							Expected: vmErr.expected,
+						},
+					})
+				} else {
+					r.logger.Error("failed to start runtime",
+						"err", err,
+					)
+				}
+
+				// Do not make any further start attempts in case the failure is permanent.
+				permanent := errors.Is(err, host.ErrPermanentInitFailure)
+				if permanent {
+					r.logger.Error("runtime failed to start permanently, not retrying")
+
+					permanentErr = err
+					ticker.Stop()
+					ticker = nil
+				}
+
+				// The runtime is no longer available, even if it was previously stopped due to
+				// being idle.
+				r.Lock()
+				r.idle = false
+				r.Unlock()
 
 				// Notify subscribers that a runtime has failed to start.
 				r.notifier.Broadcast(&host.Event{
 					FailedToStart: &host.FailedToStartEvent{
-						Error: err,
+						Error:     err,
+						Stderr:    r.stderr.Lines(),
+						Permanent: permanent,
 					},
 				})
 
 				continue
 			}
+
+			// Use a single reset timer per started process so that other events (e.g. periodic
+			// health checks) do not delay the reset.
+			resetTickerCh = time.After(resetTickerTimeout)
+
+			// Stop the runtime if it remains idle, if configured.
+			if r.cfg.IdleTimeout > 0 {
+				idleCh = time.After(r.cfg.IdleTimeout)
+			}
+
+			// Prepare a standby for the next restart, if configured.
+			startStandby()
 		}
 
 		// Wait for either the runtime or the runtime manager to terminate.
@@ -566,6 +1517,21 @@ func (r *sandboxedRuntime) manager() {
 				// Request to abort the runtime.
 				rq.ch <- r.handleAbortRequest(rq)
 				close(rq.ch)
+
+				// In case the runtime has been killed, it will be restarted.
+				restart = r.process == nil
+			case *restartRequest:
+				// Request to restart the runtime.
+				rq.ch <- r.handleRestartRequest()
+				close(rq.ch)
+
+				restart = r.process == nil
+				if restart && ticker != nil {
+					// Respawn the runtime immediately as this is not a failure.
+					ticker.Stop()
+					ticker = nil
+					resetTickerCh = nil
+				}
 			default:
 				r.logger.Error("received unknown request type",
 					"request_type", fmt.Sprintf("%T", rq),
@@ -574,40 +1540,170 @@ func (r *sandboxedRuntime) manager() {
 		case <-r.stopCh:
 			r.logger.Warn("termination requested")
 			return
+		case sp := <-standbyCh:
+			standbyPending = false
+			if sp == nil {
+				standbyRetryCh = time.After(standbyRetryInterval)
+				continue
+			}
+
+			r.logger.Info("standby runtime ready",
+				"pid", sp.process.GetPID(),
+			)
+			setStandby(sp)
+		case <-standbyWaitCh:
+			r.logger.Warn("standby runtime process has terminated unexpectedly",
+				"err", standby.process.Error(),
+			)
+
+			standby.conn.Close()
+			setStandby(nil)
+			standbyRetryCh = time.After(standbyRetryInterval)
+		case <-standbyRetryCh:
+			startStandby()
 		case <-r.process.Wait():
 			// Process has terminated.
 			r.logger.Error("runtime process has terminated unexpectedly",
 				"err", r.process.Error(),
 			)
 
-			r.conn.Close()
-			r.process = nil
-			r.Lock()
-			r.conn = nil
-			r.capabilityTEE = nil
-			r.rtVersion = nil
-			r.Unlock()
+			r.removeProcess(&host.StoppedEvent{
+				Stderr: r.stderr.Lines(),
+			})
+			restart = true
+		case <-idleCh:
+			idleTime := r.idleTime()
+			if idleTime < r.cfg.IdleTimeout {
+				idleCh = time.After(r.cfg.IdleTimeout - idleTime)
+				continue
+			}
+			idleCh = nil
+
+			r.logger.Info("stopping idle runtime",
+				"idle_time", idleTime,
+			)
+
+			// Discard any stale wake up requests so that the runtime is only started again
+			// once a new call arrives.
+			select {
+			case <-r.wakeCh:
+			default:
+			}
+
+			if err := r.terminateProcess(); err != nil {
+				r.logger.Error("failed to stop idle runtime",
+					"err", err,
+				)
+				continue
+			}
+			r.suspendProcess()
+			stopStandby()
+			idle = true
+
+			// Start the runtime immediately once needed as this is not a failure.
+			if ticker != nil {
+				ticker.Stop()
+				ticker = nil
+			}
+			resetTickerCh = nil
+		case <-resetTickerCh:
+			resetTickerCh = nil
 
-			// Notify subscribers that the runtime has stopped.
-			r.notifier.Broadcast(&host.Event{Stopped: &host.StoppedEvent{}})
-		case <-time.After(resetTickerTimeout):
 			// Reset the ticker if things work smoothly. Otherwise, keep on using the old ticker as
 			// it can happen that the runtime constantly terminates after a successful start.
 			if ticker != nil {
 				ticker.Stop()
 				ticker = nil
 			}
-		case ev := <-evCh:
-			// Update runtime's CapabilityTEE in case this is an update event.
-			if ue := ev.Updated; ue != nil {
-				r.Lock()
-				r.capabilityTEE = ue.CapabilityTEE
-				r.Unlock()
+		case <-healthCheckCh:
+			// Do not check a runtime that is busy processing calls, as it may not respond in time.
+			if healthCheckPending || r.hasActiveCalls() {
+				continue
+			}
+
+			healthCheckPending = true
+			go r.healthCheck(r.process, r.conn, healthCheckResCh)
+		case res := <-healthCheckResCh:
+			healthCheckPending = false
+
+			// Ignore results for processes that are no longer active.
+			if res.process != r.process {
+				continue
+			}
+			if res.err == nil {
+				healthCheckFailures = 0
+				continue
+			}
+			// Ignore failures in case calls are in flight, as the runtime may just be busy.
+			if r.hasActiveCalls() {
+				continue
 			}
+
+			healthCheckFailures++
+			r.logger.Warn("runtime health check failed",
+				"err", res.err,
+				"failures", healthCheckFailures,
+			)
+			if healthCheckFailures < r.cfg.HealthCheckFailureThreshold {
+				continue
+			}
+			healthCheckFailures = 0
+
+			// Runtime is not responsive, force a restart.
+			r.logger.Error("runtime is not responding, restarting")
+			if err := r.handleAbortRequest(&abortRequest{force: true}); err != nil {
+				r.logger.Error("failed to restart unresponsive runtime",
+					"err", err,
+				)
+			}
+			restart = r.process == nil
 		}
 	}
 }
 
+// getBindRO validates the requested read-only bind mounts against the allowed host path prefixes.
+func getBindRO(binds map[string]string, allowedPrefixes []string) (map[string]string, error) {
+	if len(binds) == 0 {
+		return nil, nil
+	}
+
+	bindRO := make(map[string]string, len(binds))
+	for hostPath, mountPoint := range binds {
+		if !filepath.IsAbs(hostPath) || !filepath.IsAbs(mountPoint) {
+			return nil, fmt.Errorf("bind mount paths must be absolute: %s -> %s", hostPath, mountPoint)
+		}
+		mountPoint = filepath.Clean(mountPoint)
+		if mountPoint == bindHostSocketPath {
+			return nil, fmt.Errorf("bind mount point %s is reserved", mountPoint)
+		}
+		// Resolve any symlinks so that they cannot be used to escape the allowed prefixes.
+		resolvedPath, err := filepath.EvalSymlinks(hostPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve bind mount path %s: %w", hostPath, err)
+		}
+		hostPath = resolvedPath
+
+		var allowed bool
+		for _, prefix := range allowedPrefixes {
+			// Prefixes are resolved the same way, as they may themselves contain symlinks.
+			if resolvedPrefix, perr := filepath.EvalSymlinks(prefix); perr == nil {
+				prefix = resolvedPrefix
+			}
+			rel, rerr := filepath.Rel(filepath.Clean(prefix), hostPath)
+			if rerr == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return nil, fmt.Errorf("bind mount of %s is not allowed", hostPath)
+		}
+
+		bindRO[hostPath] = mountPoint
+	}
+	return bindRO, nil
+}
+
 // New creates a new runtime provisioner that uses a local process sandbox.
 func New(cfg Config) (host.Provisioner, error) {
 	// Use a default Logger if none was provided.
@@ -622,10 +1718,11 @@ func New(cfg Config) (host.Provisioner, error) {
 				"runtime_id", hostCfg.Bundle.Manifest.ID,
 				"runtime_name", hostCfg.Bundle.Manifest.Name,
 			)
+
 			return process.Config{
 				Path: hostCfg.Bundle.Path,
 				Env: map[string]string{
-					"OASIS_WORKER_HOST": socketPath,
+					envWorkerHost: socketPath,
 				},
 				SandboxBinaryPath: cfg.SandboxBinaryPath,
 				Stdout:            logWrapper,
@@ -633,6 +1730,94 @@ func New(cfg Config) (host.Provisioner, error) {
 			}, nil
 		}
 	}
+	// Use a default RuntimeConnectTimeout if none was provided.
+	switch {
+	case cfg.RuntimeConnectTimeout == 0:
+		cfg.RuntimeConnectTimeout = defaultRuntimeConnectTimeout
+	case cfg.RuntimeConnectTimeout < 0:
+		return nil, fmt.Errorf("runtime connect timeout must be positive")
+	}
+	// Use a default RuntimeInterruptTimeout if none was provided.
+	switch {
+	case cfg.RuntimeInterruptTimeout == 0:
+		cfg.RuntimeInterruptTimeout = defaultRuntimeInterruptTimeout
+	case cfg.RuntimeInterruptTimeout < 0:
+		return nil, fmt.Errorf("runtime interrupt timeout must be positive")
+	}
+	// Use a default RuntimeKillTimeout if none was provided.
+	switch {
+	case cfg.RuntimeKillTimeout == 0:
+		cfg.RuntimeKillTimeout = defaultRuntimeKillTimeout
+	case cfg.RuntimeKillTimeout < 0:
+		return nil, fmt.Errorf("runtime kill timeout must be positive")
+	}
+	if cfg.WarmStandby && cfg.PersistentRuntimeDir != "" {
+		return nil, fmt.Errorf("warm standby cannot be used with a persistent runtime directory")
+	}
+	// Use a default TerminationGracePeriod if none was provided.
+	switch {
+	case cfg.TerminationGracePeriod == 0:
+		cfg.TerminationGracePeriod = defaultTerminationGracePeriod
+	case cfg.TerminationGracePeriod < 0:
+		return nil, fmt.Errorf("termination grace period must be positive")
+	}
+	// Make sure the concurrent request limit is valid.
+	if cfg.MaxConcurrentRequests < 0 {
+		return nil, fmt.Errorf("max concurrent requests must not be negative")
+	}
+	// Make sure health check configuration is valid.
+	if cfg.HealthCheckInterval < 0 {
+		return nil, fmt.Errorf("health check interval must not be negative")
+	}
+	switch {
+	case cfg.HealthCheckTimeout == 0:
+		cfg.HealthCheckTimeout = defaultHealthCheckTimeout
+	case cfg.HealthCheckTimeout < 0:
+		return nil, fmt.Errorf("health check timeout must be positive")
+	}
+	switch {
+	case cfg.HealthCheckFailureThreshold == 0:
+		cfg.HealthCheckFailureThreshold = defaultHealthCheckFailureThreshold
+	case cfg.HealthCheckFailureThreshold < 0:
+		return nil, fmt.Errorf("health check failure threshold must be positive")
+	}
+	// Use default restart backoff parameters if none were provided.
+	switch {
+	case cfg.RestartInitialInterval == 0:
+		cfg.RestartInitialInterval = backoff.DefaultInitialInterval
+	case cfg.RestartInitialInterval < 0:
+		return nil, fmt.Errorf("restart initial interval must be positive")
+	}
+	switch {
+	case cfg.RestartMaxInterval == 0:
+		cfg.RestartMaxInterval = max(backoff.DefaultMaxInterval, cfg.RestartInitialInterval)
+	case cfg.RestartMaxInterval < cfg.RestartInitialInterval:
+		return nil, fmt.Errorf("restart max interval must not be smaller than the initial interval")
+	}
+	switch {
+	case cfg.RestartRandomizationFactor == 0:
+		cfg.RestartRandomizationFactor = backoff.DefaultRandomizationFactor
+	case cfg.RestartRandomizationFactor < 0 || cfg.RestartRandomizationFactor > 1:
+		return nil, fmt.Errorf("restart randomization factor must be between 0 and 1")
+	}
+	// Make sure the idle timeout is valid.
+	if cfg.IdleTimeout < 0 {
+		return nil, fmt.Errorf("idle timeout must not be negative")
+	}
+	// Use a default StderrTailLines if none was provided.
+	switch {
+	case cfg.StderrTailLines == 0:
+		cfg.StderrTailLines = defaultStderrTailLines
+	case cfg.StderrTailLines < 0:
+		return nil, fmt.Errorf("stderr tail lines must be positive")
+	}
+	// Make sure resource limits are valid.
+	if cfg.CPUQuota < 0 {
+		return nil, fmt.Errorf("CPU quota must not be negative")
+	}
+	if (cfg.CPUQuota > 0 || cfg.MemoryLimitBytes > 0) && cfg.CgroupParent == "" && !cfg.InsecureNoSandbox {
+		return nil, fmt.Errorf("resource limits require a delegated parent cgroup to be configured")
+	}
 	// Make sure host environment information was provided in HostInfo.
 	if cfg.HostInfo == nil {
 		return nil, fmt.Errorf("no host information provided")
@@ -645,5 +1830,7 @@ func New(cfg Config) (host.Provisioner, error) {
 			}, nil
 		}
 	}
+	initMetrics()
+
 	return &provisioner{cfg: cfg}, nil
 }
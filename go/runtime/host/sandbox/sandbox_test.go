@@ -1,16 +1,32 @@
 package sandbox
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
 	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
 	"github.com/oasisprotocol/oasis-core/go/common/version"
 	cmt "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/api"
 	"github.com/oasisprotocol/oasis-core/go/runtime/bundle"
 	"github.com/oasisprotocol/oasis-core/go/runtime/host"
 	"github.com/oasisprotocol/oasis-core/go/runtime/host/protocol"
+	"github.com/oasisprotocol/oasis-core/go/runtime/host/sandbox/process"
 	"github.com/oasisprotocol/oasis-core/go/runtime/host/tests"
 )
 
@@ -37,10 +53,7 @@ func TestProvisionerSandbox(t *testing.T) {
 	t.Run("Naked", func(t *testing.T) {
 		tests.TestProvisioner(t, cfg, func() (host.Provisioner, error) {
 			return New(Config{
-				HostInfo: &protocol.HostInfo{
-					ConsensusBackend:         cmt.BackendName,
-					ConsensusProtocolVersion: version.Versions.ConsensusProtocol,
-				},
+				HostInfo:          newTestHostInfo(),
 				InsecureNoSandbox: true,
 				SandboxBinaryPath: bwrapPath,
 			})
@@ -50,12 +63,1656 @@ func TestProvisionerSandbox(t *testing.T) {
 	t.Run("Sandboxed", func(t *testing.T) {
 		tests.TestProvisioner(t, cfg, func() (host.Provisioner, error) {
 			return New(Config{
-				HostInfo: &protocol.HostInfo{
-					ConsensusBackend:         cmt.BackendName,
-					ConsensusProtocolVersion: version.Versions.ConsensusProtocol,
-				},
+				HostInfo:          newTestHostInfo(),
 				SandboxBinaryPath: bwrapPath,
 			})
 		}, nil)
 	})
 }
+
+type testProcess struct {
+	killOnce   sync.Once
+	waitCh     chan struct{}
+	ignoreKill bool
+}
+
+func (p *testProcess) GetPID() int {
+	return 0
+}
+
+func (p *testProcess) Wait() <-chan struct{} {
+	return p.waitCh
+}
+
+func (p *testProcess) Error() error {
+	return nil
+}
+
+func (p *testProcess) Kill() {
+	if p.ignoreKill {
+		return
+	}
+	p.killOnce.Do(func() {
+		close(p.waitCh)
+	})
+}
+
+func (p *testProcess) Terminate(time.Duration) {
+	p.Kill()
+}
+
+type testConnection struct {
+	protocol.Connection
+}
+
+func (c *testConnection) Call(context.Context, *protocol.Body) (*protocol.Body, error) {
+	return nil, errors.New("runtime not responding")
+}
+
+func (c *testConnection) Close() {
+}
+
+// newTestHostInfo returns the host information used by tests.
+func newTestHostInfo() *protocol.HostInfo {
+	return &protocol.HostInfo{
+		ConsensusBackend:         cmt.BackendName,
+		ConsensusProtocolVersion: version.Versions.ConsensusProtocol,
+	}
+}
+
+// newTestSandboxedRuntime returns a sandboxed runtime with a running test process, which can be
+// used to exercise the runtime manager without spawning any processes. Restarting the runtime is
+// not supported and always fails.
+func newTestSandboxedRuntime(t *testing.T) (*sandboxedRuntime, *testProcess) {
+	p := &testProcess{waitCh: make(chan struct{})}
+	r := &sandboxedRuntime{
+		cfg: Config{
+			GetSandboxConfig: func(host.Config, string, string) (process.Config, error) {
+				return process.Config{}, errors.New("restart not supported in tests")
+			},
+			RuntimeInterruptTimeout:     defaultRuntimeInterruptTimeout,
+			RuntimeKillTimeout:          defaultRuntimeKillTimeout,
+			TerminationGracePeriod:      defaultTerminationGracePeriod,
+			HealthCheckTimeout:          defaultHealthCheckTimeout,
+			HealthCheckFailureThreshold: defaultHealthCheckFailureThreshold,
+			InsecureNoSandbox:           true,
+		},
+		id:       common.NewTestNamespaceFromSeed([]byte(t.Name()), 0),
+		stopCh:   make(chan struct{}),
+		ctrlCh:   make(chan interface{}, ctrlChannelBufferSize),
+		wakeCh:   make(chan struct{}, 1),
+		process:  p,
+		conn:     &testConnection{},
+		notifier: pubsub.NewBroker(false),
+		stderr:   &stderrTail{},
+		logger:   logging.GetLogger("runtime/host/sandbox/test"),
+	}
+	return r, p
+}
+
+func TestWaitForUnavailable(t *testing.T) {
+	require := require.New(t)
+
+	r, p := newTestSandboxedRuntime(t)
+	r.Start()
+	defer r.Stop()
+
+	// Cancelled context should return the context error while the runtime is running.
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	err := r.WaitForUnavailable(ctx)
+	require.ErrorIs(err, context.DeadlineExceeded, "WaitForUnavailable should fail on context timeout")
+
+	// Killing the process should unblock the waiter.
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- r.WaitForUnavailable(context.Background())
+	}()
+	require.Never(func() bool {
+		return len(errCh) > 0
+	}, 100*time.Millisecond, 10*time.Millisecond, "WaitForUnavailable should block while the runtime is running")
+	p.Kill()
+
+	select {
+	case err = <-errCh:
+		require.NoError(err, "WaitForUnavailable")
+	case <-time.After(5 * time.Second):
+		t.Fatalf("WaitForUnavailable did not return after the runtime was killed")
+	}
+
+	// Runtime is no longer running so waiting should return immediately.
+	err = r.WaitForUnavailable(context.Background())
+	require.NoError(err, "WaitForUnavailable should return immediately when the runtime is down")
+}
+
+func TestRuntimeConnectTimeout(t *testing.T) {
+	require := require.New(t)
+
+	hostInfo := newTestHostInfo()
+
+	_, err := New(Config{
+		HostInfo:              hostInfo,
+		RuntimeConnectTimeout: -time.Second,
+	})
+	require.Error(err, "New should reject negative runtime connect timeouts")
+
+	// Use a runtime that never connects.
+	p, err := New(Config{
+		GetSandboxConfig: func(host.Config, string, string) (process.Config, error) {
+			return process.Config{
+				Path:   "/bin/sleep",
+				Args:   []string{"60"},
+				Stdout: io.Discard,
+				Stderr: io.Discard,
+			}, nil
+		},
+		HostInfo:              hostInfo,
+		InsecureNoSandbox:     true,
+		RuntimeConnectTimeout: 100 * time.Millisecond,
+	})
+	require.NoError(err, "New")
+
+	var id common.Namespace
+	r, err := p.NewRuntime(host.Config{
+		Bundle: &host.RuntimeBundle{
+			Bundle: &bundle.Bundle{
+				Manifest: &bundle.Manifest{ID: id},
+			},
+		},
+	})
+	require.NoError(err, "NewRuntime")
+
+	evCh, evSub := r.WatchEvents()
+	defer evSub.Close()
+
+	r.Start()
+
+	select {
+	case ev := <-evCh:
+		require.NotNil(ev.FailedToStart, "runtime should fail to start")
+		require.ErrorIs(ev.FailedToStart.Error, os.ErrDeadlineExceeded, "runtime should fail to connect")
+	case <-time.After(5 * time.Second):
+		t.Fatalf("runtime did not fail to start in time")
+	}
+
+	// Wait for the runtime to stop so that no processes are left behind.
+	r.Stop()
+	for ev := range evCh {
+		if ev.Stopped != nil {
+			break
+		}
+	}
+}
+
+func TestRestartMetrics(t *testing.T) {
+	require := require.New(t)
+
+	r, p := newTestSandboxedRuntime(t)
+	labels := r.getMetricLabels()
+	restarts := testutil.ToFloat64(restartCount.With(labels))
+
+	evCh, evSub := r.WatchEvents()
+	defer evSub.Close()
+
+	r.Start()
+	defer r.Stop()
+
+	// Killing the process should trigger a restart.
+	p.Kill()
+
+	for ev := range evCh {
+		if ev.FailedToStart != nil {
+			break
+		}
+	}
+	require.EqualValues(restarts+1, testutil.ToFloat64(restartCount.With(labels)), "restart count")
+	// Failed start attempts are retried, but do not count as restarts.
+	require.GreaterOrEqual(testutil.ToFloat64(currentAttempt.With(labels)), 1.0, "current attempt")
+}
+
+func TestHealthCheck(t *testing.T) {
+	require := require.New(t)
+
+	r, p := newTestSandboxedRuntime(t)
+	r.cfg.HealthCheckInterval = 10 * time.Millisecond
+	r.cfg.HealthCheckFailureThreshold = 2
+
+	evCh, evSub := r.WatchEvents()
+	defer evSub.Close()
+
+	r.Start()
+	defer r.Stop()
+
+	// Runtime that does not respond to pings should be restarted.
+	select {
+	case ev := <-evCh:
+		require.NotNil(ev.Stopped, "unresponsive runtime should be stopped")
+	case <-time.After(5 * time.Second):
+		t.Fatalf("unresponsive runtime was not restarted")
+	}
+
+	select {
+	case <-p.Wait():
+	default:
+		t.Fatalf("unresponsive runtime process should be killed")
+	}
+}
+
+// hangingConnection is a connection to a runtime that is alive, but does not respond to pings.
+type hangingConnection struct {
+	protocol.Connection
+
+	pings atomic.Int32
+}
+
+func (c *hangingConnection) Call(ctx context.Context, body *protocol.Body) (*protocol.Body, error) {
+	if body.RuntimeAbortRequest != nil {
+		return &protocol.Body{RuntimeAbortResponse: &protocol.Empty{}}, nil
+	}
+
+	c.pings.Add(1)
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (c *hangingConnection) Close() {
+}
+
+func TestHealthCheckBusy(t *testing.T) {
+	require := require.New(t)
+
+	conn := &hangingConnection{}
+	r, _ := newTestSandboxedRuntime(t)
+	r.conn = conn
+	r.cfg.HealthCheckInterval = 10 * time.Millisecond
+	r.cfg.HealthCheckTimeout = 10 * time.Millisecond
+	r.cfg.HealthCheckFailureThreshold = 2
+
+	evCh, evSub := r.WatchEvents()
+	defer evSub.Close()
+
+	// Runtime that is busy processing a call should not be health checked.
+	r.callStarted()
+	r.Start()
+	defer r.Stop()
+
+	select {
+	case ev := <-evCh:
+		t.Fatalf("unexpected event while the runtime is busy: %s", ev.Kind())
+	case <-time.After(200 * time.Millisecond):
+	}
+	require.Zero(conn.pings.Load(), "busy runtime should not be pinged")
+
+	// Once the call completes, the unresponsive runtime should be restarted.
+	r.callFinished()
+	select {
+	case ev := <-evCh:
+		require.NotNil(ev.Stopped, "unresponsive runtime should be stopped")
+	case <-time.After(5 * time.Second):
+		t.Fatalf("unresponsive runtime was not restarted")
+	}
+}
+
+func TestHealthCheckNonBlocking(t *testing.T) {
+	require := require.New(t)
+
+	conn := &hangingConnection{}
+	r, _ := newTestSandboxedRuntime(t)
+	r.conn = conn
+	r.cfg.HealthCheckInterval = 10 * time.Millisecond
+	r.cfg.HealthCheckTimeout = time.Minute
+
+	r.Start()
+	defer r.Stop()
+
+	require.Eventually(func() bool {
+		return conn.pings.Load() > 0
+	}, 5*time.Second, 10*time.Millisecond, "runtime should be pinged")
+
+	// Pending health checks should not block other requests.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	err := r.Abort(ctx, false)
+	require.NoError(err, "Abort should be handled while a health check is pending")
+	require.EqualValues(1, conn.pings.Load(), "only a single health check should be pending")
+}
+
+func TestPersistentRuntimeDir(t *testing.T) {
+	require := require.New(t)
+
+	baseDir := t.TempDir()
+
+	// Record the runtime directory on each start attempt and write a file into it on the first
+	// one. All attempts fail, causing the runtime to be restarted.
+	dirCh := make(chan string, 16)
+	r, _ := newTestSandboxedRuntime(t)
+	r.cfg.GetSandboxConfig = func(_ host.Config, _ string, runtimeDir string) (process.Config, error) {
+		if _, err := os.Stat(filepath.Join(runtimeDir, "cache")); os.IsNotExist(err) {
+			if err = os.WriteFile(filepath.Join(runtimeDir, "cache"), []byte("warm"), 0o600); err != nil {
+				return process.Config{}, err
+			}
+		}
+		dirCh <- runtimeDir
+		return process.Config{}, errors.New("restart not supported in tests")
+	}
+	r.cfg.PersistentRuntimeDir = baseDir
+	r.process = nil
+	r.conn = nil
+
+	evCh, evSub := r.WatchEvents()
+	defer evSub.Close()
+
+	r.Start()
+
+	recvDir := func() string {
+		select {
+		case dir := <-dirCh:
+			return dir
+		case <-time.After(5 * time.Second):
+			t.Fatalf("runtime was not started")
+			return ""
+		}
+	}
+	expectedDir := filepath.Join(baseDir, r.id.String())
+	require.Equal(expectedDir, recvDir(), "runtime directory on first start")
+	require.Equal(expectedDir, recvDir(), "runtime directory on restart")
+
+	data, err := os.ReadFile(filepath.Join(expectedDir, "cache"))
+	require.NoError(err, "file written on first start should survive restarts")
+	require.Equal("warm", string(data))
+
+	// Stopping the runtime should remove the directory.
+	r.Stop()
+	for ev := range evCh {
+		if ev.Stopped != nil {
+			break
+		}
+	}
+	require.NoDirExists(expectedDir, "runtime directory should be removed on stop")
+}
+
+func TestWatchEventsFiltered(t *testing.T) {
+	require := require.New(t)
+
+	r, _ := newTestSandboxedRuntime(t)
+
+	ch, sub := r.WatchEventsFiltered(host.EventKindStopped)
+	defer sub.Close()
+
+	r.EmitEvent(&host.Event{Started: &host.StartedEvent{}})
+	r.EmitEvent(&host.Event{Updated: &host.UpdatedEvent{}})
+	r.EmitEvent(&host.Event{FailedToStart: &host.FailedToStartEvent{}})
+	r.EmitEvent(&host.Event{Stopped: &host.StoppedEvent{}})
+	r.EmitEvent(&host.Event{ConfigUpdated: &host.ConfigUpdatedEvent{}})
+
+	select {
+	case ev := <-ch:
+		require.Equal(host.EventKindStopped, ev.Kind(), "only stopped events should be delivered")
+	case <-time.After(time.Second):
+		t.Fatalf("failed to receive stopped event")
+	}
+
+	select {
+	case ev := <-ch:
+		t.Fatalf("unexpected event delivered: %s", ev.Kind())
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestGetProcessInfo(t *testing.T) {
+	require := require.New(t)
+
+	r, _ := newTestSandboxedRuntime(t)
+	r.conn = nil
+
+	// Process information is not available before the runtime has been started.
+	_, err := r.GetProcessInfo()
+	require.ErrorIs(err, errRuntimeNotReady, "GetProcessInfo should fail when no process is running")
+
+	startTime := time.Now()
+	r.conn = &testConnection{}
+	r.pid = 42
+	r.startTime = startTime
+	r.restartCount = 3
+
+	pi, err := r.GetProcessInfo()
+	require.NoError(err, "GetProcessInfo")
+	require.Equal(42, pi.PID)
+	require.Equal(startTime, pi.StartTime)
+	require.EqualValues(3, pi.RestartCount)
+}
+
+func TestEmitUpdatedEvent(t *testing.T) {
+	require := require.New(t)
+
+	r, _ := newTestSandboxedRuntime(t)
+
+	evCh, evSub := r.WatchEvents()
+	defer evSub.Close()
+
+	emitUpdate := func(capabilityTEE *node.CapabilityTEE) *host.UpdatedEvent {
+		r.EmitEvent(&host.Event{Updated: &host.UpdatedEvent{CapabilityTEE: capabilityTEE}})
+
+		select {
+		case ev := <-evCh:
+			require.NotNil(ev.Updated, "should have received an update event")
+			return ev.Updated
+		case <-time.After(time.Second):
+			t.Fatalf("failed to receive update event")
+			return nil
+		}
+	}
+
+	capabilityTEE := &node.CapabilityTEE{
+		Hardware:    node.TEEHardwareIntelSGX,
+		Attestation: []byte("quote"),
+	}
+	ev := emitUpdate(capabilityTEE)
+	require.True(ev.Changed.Has(host.CapabilityTEEChangeHardware | host.CapabilityTEEChangeAttestation))
+
+	// Only update the quote.
+	updated := *capabilityTEE
+	updated.Attestation = []byte("new quote")
+	ev = emitUpdate(&updated)
+	require.Equal(host.CapabilityTEEChangeAttestation, ev.Changed, "only the quote should have changed")
+
+	cached, err := r.GetCapabilityTEE()
+	require.NoError(err, "GetCapabilityTEE")
+	require.Equal(&updated, cached, "cached CapabilityTEE should be updated")
+
+	// Same CapabilityTEE again.
+	ev = emitUpdate(&updated)
+	require.Zero(ev.Changed, "nothing should have changed")
+}
+
+func TestAbortKillTimeout(t *testing.T) {
+	require := require.New(t)
+
+	// Process that ignores being killed (e.g., stuck in uninterruptible IO).
+	r, p := newTestSandboxedRuntime(t)
+	p.ignoreKill = true
+	r.cfg.RuntimeInterruptTimeout = 10 * time.Millisecond
+	r.cfg.RuntimeKillTimeout = 100 * time.Millisecond
+
+	start := time.Now()
+	err := r.handleAbortRequest(&abortRequest{})
+	require.ErrorIs(err, errRuntimeNotStopped, "abort should fail when the runtime does not terminate")
+	require.GreaterOrEqual(time.Since(start), r.cfg.RuntimeKillTimeout, "abort should wait for the kill deadline")
+	require.NotNil(r.process, "process should not be removed while it is still running")
+}
+
+func TestStopKillTimeout(t *testing.T) {
+	require := require.New(t)
+
+	// Process that ignores being killed (e.g., stuck in uninterruptible IO).
+	r, p := newTestSandboxedRuntime(t)
+	p.ignoreKill = true
+	r.cfg.TerminationGracePeriod = 10 * time.Millisecond
+	r.cfg.RuntimeKillTimeout = 100 * time.Millisecond
+
+	evCh, evSub := r.WatchEvents()
+	defer evSub.Close()
+
+	r.Start()
+	r.Stop()
+
+	// Stopping the runtime should not wait for the process to terminate indefinitely.
+	select {
+	case ev := <-evCh:
+		require.NotNil(ev.Stopped, "runtime should stop")
+	case <-time.After(5 * time.Second):
+		t.Fatalf("runtime did not stop after the kill deadline")
+	}
+}
+
+func TestRestartRequest(t *testing.T) {
+	require := require.New(t)
+
+	r, _ := newTestSandboxedRuntime(t)
+	r.cfg.RuntimeKillTimeout = 100 * time.Millisecond
+
+	evCh, evSub := r.WatchEvents()
+	defer evSub.Close()
+
+	err := r.handleRestartRequest()
+	require.NoError(err, "handleRestartRequest")
+	require.Nil(r.process, "process should be removed so that it is respawned")
+	require.Nil(r.conn, "connection should be removed")
+
+	select {
+	case ev := <-evCh:
+		require.NotNil(ev.Stopped, "should have received a stop event")
+	case <-time.After(time.Second):
+		t.Fatalf("failed to receive stop event")
+	}
+
+	// Restart requests should fail once the runtime has been stopped.
+	close(r.stopCh)
+	err = r.Restart(context.Background())
+	require.ErrorIs(err, errRuntimeStopped, "Restart should fail after the runtime has been stopped")
+}
+
+func TestInMemoryBinary(t *testing.T) {
+	require := require.New(t)
+
+	// Use a copy of the shell as an in-memory ELF binary.
+	binary, err := os.ReadFile("/bin/sh")
+	if err != nil {
+		t.Skipf("skipping as /bin/sh is not available: %s", err)
+	}
+
+	marker := filepath.Join(t.TempDir(), "ran")
+	pathCh := make(chan string, 1)
+	p, err := New(Config{
+		GetSandboxConfig: func(cfg host.Config, _, _ string) (process.Config, error) {
+			select {
+			case pathCh <- cfg.Bundle.Path:
+			default:
+			}
+			return process.Config{
+				Path:   cfg.Bundle.Path,
+				Args:   []string{"-c", "touch " + marker},
+				Stdout: io.Discard,
+				Stderr: io.Discard,
+			}, nil
+		},
+		HostInfo:          newTestHostInfo(),
+		InsecureNoSandbox: true,
+	})
+	require.NoError(err, "New")
+
+	var id common.Namespace
+	r, err := p.NewRuntime(host.Config{
+		Bundle: &host.RuntimeBundle{
+			Bundle: &bundle.Bundle{
+				Manifest: &bundle.Manifest{ID: id},
+			},
+			Path:   "/nonexistent",
+			Binary: bytes.NewReader(binary),
+		},
+	})
+	require.NoError(err, "NewRuntime")
+
+	evCh, evSub := r.WatchEvents()
+	defer evSub.Close()
+
+	r.Start()
+	defer func() {
+		r.Stop()
+		for ev := range evCh {
+			if ev.Stopped != nil {
+				break
+			}
+		}
+	}()
+
+	// The binary exits without connecting, so the runtime fails to start.
+	select {
+	case ev := <-evCh:
+		require.NotNil(ev.FailedToStart, "runtime should fail to start")
+	case <-time.After(5 * time.Second):
+		t.Fatalf("runtime did not fail to start in time")
+	}
+
+	path := <-pathCh
+	require.NotEqual("/nonexistent", path, "in-memory binary should be materialized")
+	require.FileExists(marker, "materialized binary should run")
+}
+
+type testRuntimeHandler struct {
+	version version.Version
+}
+
+func (h *testRuntimeHandler) Handle(_ context.Context, body *protocol.Body) (*protocol.Body, error) {
+	switch {
+	case body.RuntimeInfoRequest != nil:
+		return &protocol.Body{
+			RuntimeInfoResponse: &protocol.RuntimeInfoResponse{
+				ProtocolVersion: version.RuntimeHostProtocol,
+				RuntimeVersion:  h.version,
+			},
+		}, nil
+	case body.RuntimePingRequest != nil:
+		return &protocol.Body{Empty: &protocol.Empty{}}, nil
+	}
+	return nil, errors.New("method not supported")
+}
+
+// fakeRuntime keeps track of fake runtimes connected to the host.
+type fakeRuntime struct {
+	sync.Mutex
+
+	conns []protocol.Connection
+
+	// beforeConnect is an optional function called before a fake runtime connects to the host,
+	// with the number of fake runtimes that have connected so far.
+	beforeConnect func(connected int)
+}
+
+// numConnected returns the number of fake runtimes that have connected to the host.
+func (fr *fakeRuntime) numConnected() int {
+	fr.Lock()
+	defer fr.Unlock()
+	return len(fr.conns)
+}
+
+// newFakeRuntimeConfig returns a provisioner configuration that spawns processes which do nothing,
+// with a fake runtime reporting the given version connecting to the host in their place.
+func newFakeRuntimeConfig(t *testing.T, id common.Namespace, rtVersion version.Version) (Config, *fakeRuntime) {
+	logger := logging.GetLogger("runtime/host/sandbox/test")
+	fr := &fakeRuntime{}
+	t.Cleanup(func() {
+		fr.Lock()
+		defer fr.Unlock()
+		for _, c := range fr.conns {
+			c.Close()
+		}
+	})
+
+	cfg := Config{
+		GetSandboxConfig: func(_ host.Config, socketPath, _ string) (process.Config, error) {
+			if fr.beforeConnect != nil {
+				fr.beforeConnect(fr.numConnected())
+			}
+
+			conn, err := net.Dial("unix", socketPath)
+			if err != nil {
+				return process.Config{}, err
+			}
+			pc, err := protocol.NewConnection(logger, id, &testRuntimeHandler{version: rtVersion})
+			if err != nil {
+				return process.Config{}, err
+			}
+			if err = pc.InitGuest(conn); err != nil {
+				return process.Config{}, err
+			}
+
+			fr.Lock()
+			fr.conns = append(fr.conns, pc)
+			fr.Unlock()
+
+			return process.Config{
+				Path:   "/bin/sleep",
+				Args:   []string{"60"},
+				Stdout: io.Discard,
+				Stderr: io.Discard,
+			}, nil
+		},
+		HostInfo:          newTestHostInfo(),
+		InsecureNoSandbox: true,
+	}
+	return cfg, fr
+}
+
+func TestVersionMismatch(t *testing.T) {
+	require := require.New(t)
+
+	id := common.NewTestNamespaceFromSeed([]byte("sandbox version mismatch test"), 0)
+	reported := version.Version{Major: 2}
+	expected := version.Version{Major: 1}
+
+	// Use a fake runtime reporting a version that differs from the bundle version.
+	cfg, _ := newFakeRuntimeConfig(t, id, reported)
+	p, err := New(cfg)
+	require.NoError(err, "New")
+
+	r, err := p.NewRuntime(host.Config{
+		Bundle: &host.RuntimeBundle{
+			Bundle: &bundle.Bundle{
+				Manifest: &bundle.Manifest{ID: id, Version: expected},
+			},
+		},
+	})
+	require.NoError(err, "NewRuntime")
+
+	evCh, evSub := r.WatchEvents()
+	defer evSub.Close()
+
+	r.Start()
+	defer func() {
+		r.Stop()
+		for ev := range evCh {
+			if ev.Stopped != nil {
+				break
+			}
+		}
+	}()
+
+	select {
+	case ev := <-evCh:
+		require.NotNil(ev.VersionMismatch, "runtime should report a version mismatch")
+		require.Equal(reported, ev.VersionMismatch.Reported)
+		require.Equal(expected, ev.VersionMismatch.Expected)
+	case <-time.After(5 * time.Second):
+		t.Fatalf("runtime did not report a version mismatch in time")
+	}
+
+	select {
+	case ev := <-evCh:
+		require.NotNil(ev.FailedToStart, "runtime should fail to start")
+	case <-time.After(5 * time.Second):
+		t.Fatalf("runtime did not fail to start in time")
+	}
+}
+
+func TestIdleTimeout(t *testing.T) {
+	require := require.New(t)
+
+	id := common.NewTestNamespaceFromSeed([]byte("sandbox idle timeout test"), 0)
+	rtVersion := version.Version{Major: 1}
+
+	_, err := New(Config{
+		HostInfo:    &protocol.HostInfo{},
+		IdleTimeout: -time.Second,
+	})
+	require.Error(err, "New should reject a negative idle timeout")
+
+	cfg, _ := newFakeRuntimeConfig(t, id, rtVersion)
+	cfg.IdleTimeout = 200 * time.Millisecond
+	p, err := New(cfg)
+	require.NoError(err, "New")
+
+	r, err := p.NewRuntime(host.Config{
+		Bundle: &host.RuntimeBundle{
+			Bundle: &bundle.Bundle{
+				Manifest: &bundle.Manifest{ID: id, Version: rtVersion},
+			},
+		},
+	})
+	require.NoError(err, "NewRuntime")
+
+	evCh, evSub := r.WatchEvents()
+	defer evSub.Close()
+
+	r.Start()
+	defer r.Stop()
+
+	recvEvent := func() *host.Event {
+		select {
+		case ev := <-evCh:
+			return ev
+		case <-time.After(5 * time.Second):
+			t.Fatalf("failed to receive runtime event")
+			return nil
+		}
+	}
+
+	require.NotNil(recvEvent().Started, "runtime should start")
+
+	// The runtime process should be stopped after being idle.
+	ev := recvEvent()
+	require.NotNil(ev.Stopped, "idle runtime process should be stopped")
+	require.True(ev.Stopped.Idle, "stop should be reported as idle")
+	_, err = r.GetProcessInfo()
+	require.ErrorIs(err, errRuntimeNotReady, "GetProcessInfo should fail while idle")
+
+	// The runtime should not be restarted until a call arrives.
+	select {
+	case ev = <-evCh:
+		t.Fatalf("unexpected event while idle: %s", ev.Kind())
+	case <-time.After(300 * time.Millisecond):
+	}
+
+	// The runtime should still be reported as available while idle.
+	activeVersion, err := r.GetActiveVersion()
+	require.NoError(err, "GetActiveVersion")
+	require.Equal(rtVersion, *activeVersion)
+	_, err = r.GetCapabilityTEE()
+	require.NoError(err, "GetCapabilityTEE")
+
+	waitCtx, waitCancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer waitCancel()
+	err = r.(*sandboxedRuntime).WaitForUnavailable(waitCtx)
+	require.ErrorIs(err, context.DeadlineExceeded, "idle runtime should not be unavailable")
+
+	// A call should start the runtime again.
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err = r.Call(ctx, &protocol.Body{RuntimePingRequest: &protocol.Empty{}})
+	require.NoError(err, "Call")
+	require.NotNil(recvEvent().Started, "runtime should be started again on call")
+}
+
+func TestIdleControlRequests(t *testing.T) {
+	require := require.New(t)
+
+	id := common.NewTestNamespaceFromSeed([]byte("sandbox idle control requests test"), 0)
+	rtVersion := version.Version{Major: 1}
+
+	cfg, fr := newFakeRuntimeConfig(t, id, rtVersion)
+	cfg.IdleTimeout = 200 * time.Millisecond
+	p, err := New(cfg)
+	require.NoError(err, "New")
+
+	r, err := p.NewRuntime(host.Config{
+		Bundle: &host.RuntimeBundle{
+			Bundle: &bundle.Bundle{
+				Manifest: &bundle.Manifest{ID: id, Version: rtVersion},
+			},
+		},
+	})
+	require.NoError(err, "NewRuntime")
+
+	evCh, evSub := r.WatchEvents()
+	defer evSub.Close()
+
+	r.Start()
+	defer r.Stop()
+
+	recvEvent := func() *host.Event {
+		select {
+		case ev := <-evCh:
+			return ev
+		case <-time.After(5 * time.Second):
+			t.Fatalf("failed to receive runtime event")
+			return nil
+		}
+	}
+	waitIdle := func() {
+		ev := recvEvent()
+		require.NotNil(ev.Stopped, "idle runtime process should be stopped")
+		require.True(ev.Stopped.Idle, "stop should be reported as idle")
+	}
+
+	require.NotNil(recvEvent().Started, "runtime should start")
+	waitIdle()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Aborting an idle runtime should return immediately without starting it.
+	err = r.Abort(ctx, false)
+	require.NoError(err, "Abort should succeed while idle")
+	err = r.Abort(ctx, true)
+	require.NoError(err, "forced Abort should succeed while idle")
+	select {
+	case ev := <-evCh:
+		t.Fatalf("unexpected event after aborting an idle runtime: %s", ev.Kind())
+	case <-time.After(100 * time.Millisecond):
+	}
+	require.Equal(1, fr.numConnected(), "runtime should not be started by an abort")
+
+	// Restarting an idle runtime should start it again.
+	err = r.Restart(ctx)
+	require.NoError(err, "Restart should succeed while idle")
+	require.NotNil(recvEvent().Started, "runtime should be started by a restart")
+	require.Equal(2, fr.numConnected(), "runtime should be started by a restart")
+	_, err = r.GetProcessInfo()
+	require.NoError(err, "GetProcessInfo")
+
+	// The runtime should become idle again.
+	waitIdle()
+}
+
+func TestPermanentInitFailure(t *testing.T) {
+	require := require.New(t)
+
+	id := common.NewTestNamespaceFromSeed([]byte("sandbox permanent init failure test"), 0)
+	rtVersion := version.Version{Major: 1}
+
+	cfg, fr := newFakeRuntimeConfig(t, id, rtVersion)
+	cfg.HostInitializer = func(context.Context, *HostInitializerParams) (*host.StartedEvent, error) {
+		return nil, fmt.Errorf("%w: attestation policy violation", host.ErrPermanentInitFailure)
+	}
+	cfg.RestartInitialInterval = 10 * time.Millisecond
+	p, err := New(cfg)
+	require.NoError(err, "New")
+
+	r, err := p.NewRuntime(host.Config{
+		Bundle: &host.RuntimeBundle{
+			Bundle: &bundle.Bundle{
+				Manifest: &bundle.Manifest{ID: id, Version: rtVersion},
+			},
+		},
+	})
+	require.NoError(err, "NewRuntime")
+
+	evCh, evSub := r.WatchEvents()
+	defer evSub.Close()
+
+	r.Start()
+	defer r.Stop()
+
+	select {
+	case ev := <-evCh:
+		require.NotNil(ev.FailedToStart, "runtime should fail to start")
+		require.True(ev.FailedToStart.Permanent, "failure should be permanent")
+		require.ErrorIs(ev.FailedToStart.Error, host.ErrPermanentInitFailure)
+	case <-time.After(5 * time.Second):
+		t.Fatalf("runtime did not fail to start in time")
+	}
+
+	// No further start attempts should be made.
+	select {
+	case ev := <-evCh:
+		t.Fatalf("unexpected event after permanent failure: %s", ev.Kind())
+	case <-time.After(500 * time.Millisecond):
+	}
+	require.Equal(1, fr.numConnected(), "runtime should only be started once")
+
+	// Requests should be rejected.
+	err = r.Abort(context.Background(), true)
+	require.ErrorIs(err, host.ErrPermanentInitFailure, "Abort should fail after a permanent failure")
+}
+
+type blockingConnection struct {
+	protocol.Connection
+
+	callCh    chan struct{}
+	releaseCh chan struct{}
+}
+
+func (c *blockingConnection) Call(ctx context.Context, _ *protocol.Body) (*protocol.Body, error) {
+	c.callCh <- struct{}{}
+	select {
+	case <-c.releaseCh:
+		return &protocol.Body{Empty: &protocol.Empty{}}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func TestMaxConcurrentRequests(t *testing.T) {
+	require := require.New(t)
+
+	hostInfo := newTestHostInfo()
+
+	_, err := New(Config{
+		HostInfo:              hostInfo,
+		MaxConcurrentRequests: -1,
+	})
+	require.Error(err, "New should reject a negative concurrent request limit")
+
+	p, err := New(Config{
+		HostInfo:              hostInfo,
+		MaxConcurrentRequests: 1,
+	})
+	require.NoError(err, "New")
+
+	var id common.Namespace
+	rt, err := p.NewRuntime(host.Config{
+		Bundle: &host.RuntimeBundle{
+			Bundle: &bundle.Bundle{
+				Manifest: &bundle.Manifest{ID: id},
+			},
+		},
+	})
+	require.NoError(err, "NewRuntime")
+
+	conn := &blockingConnection{
+		callCh:    make(chan struct{}, 2),
+		releaseCh: make(chan struct{}),
+	}
+	r := rt.(*sandboxedRuntime)
+	r.conn = conn
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	call := func() <-chan error {
+		errCh := make(chan error, 1)
+		go func() {
+			_, err := r.Call(ctx, &protocol.Body{RuntimePingRequest: &protocol.Empty{}})
+			errCh <- err
+		}()
+		return errCh
+	}
+
+	// First call should reach the runtime.
+	errCh1 := call()
+	select {
+	case <-conn.callCh:
+	case <-time.After(time.Second):
+		t.Fatalf("first call did not reach the runtime")
+	}
+
+	// Second call should block until the first one completes.
+	errCh2 := call()
+	select {
+	case <-conn.callCh:
+		t.Fatalf("second call should block while the first one is in flight")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	conn.releaseCh <- struct{}{}
+	require.NoError(<-errCh1, "first call")
+
+	select {
+	case <-conn.callCh:
+	case <-time.After(time.Second):
+		t.Fatalf("second call did not reach the runtime")
+	}
+	conn.releaseCh <- struct{}{}
+	require.NoError(<-errCh2, "second call")
+
+	// Waiting callers should respect context cancellation.
+	errCh1 = call()
+	<-conn.callCh
+	waitCtx, waitCancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer waitCancel()
+	_, err = r.Call(waitCtx, &protocol.Body{RuntimePingRequest: &protocol.Empty{}})
+	require.ErrorIs(err, context.DeadlineExceeded, "waiting call should be canceled")
+
+	conn.releaseCh <- struct{}{}
+	require.NoError(<-errCh1, "third call")
+}
+
+func TestExtraEnv(t *testing.T) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	output := filepath.Join(dir, "output")
+	script := filepath.Join(dir, "runtime.sh")
+	err := os.WriteFile(script, []byte("#!/bin/sh\necho \"$TEST_VAR\" > \"$TEST_OUTPUT\"\n"), 0o700) // nolint: gosec
+	require.NoError(err, "WriteFile")
+
+	p, err := New(Config{
+		HostInfo:          newTestHostInfo(),
+		InsecureNoSandbox: true,
+	})
+	require.NoError(err, "New")
+
+	startRuntime := func(extraEnv map[string]string) *host.FailedToStartEvent {
+		var id common.Namespace
+		r, err := p.NewRuntime(host.Config{
+			Bundle: &host.RuntimeBundle{
+				Bundle: &bundle.Bundle{
+					Manifest: &bundle.Manifest{ID: id},
+				},
+				Path: script,
+			},
+			ExtraEnv: extraEnv,
+		})
+		require.NoError(err, "NewRuntime")
+
+		evCh, evSub := r.WatchEvents()
+		defer evSub.Close()
+
+		r.Start()
+		defer func() {
+			r.Stop()
+			for ev := range evCh {
+				if ev.Stopped != nil {
+					break
+				}
+			}
+		}()
+
+		// The script exits without connecting, so the runtime fails to start.
+		select {
+		case ev := <-evCh:
+			require.NotNil(ev.FailedToStart, "runtime should fail to start")
+			return ev.FailedToStart
+		case <-time.After(5 * time.Second):
+			t.Fatalf("runtime did not fail to start in time")
+			return nil
+		}
+	}
+
+	// Extra environment variables should reach the runtime process.
+	startRuntime(map[string]string{
+		"TEST_VAR":    "hello world",
+		"TEST_OUTPUT": output,
+	})
+	data, err := os.ReadFile(output)
+	require.NoError(err, "runtime should have written the output")
+	require.Equal("hello world\n", string(data))
+
+	// Reserved environment variables cannot be overridden.
+	ev := startRuntime(map[string]string{
+		envWorkerHost: "/tmp/evil.sock",
+	})
+	require.ErrorContains(ev.Error, "reserved", "reserved environment variables should be rejected")
+}
+
+func TestRestartBackOff(t *testing.T) {
+	require := require.New(t)
+
+	hostInfo := newTestHostInfo()
+
+	// Invalid configurations should be rejected.
+	for _, cfg := range []Config{
+		{RestartInitialInterval: -time.Second},
+		{RestartInitialInterval: 2 * time.Second, RestartMaxInterval: time.Second},
+		{RestartRandomizationFactor: -0.1},
+		{RestartRandomizationFactor: 1.1},
+	} {
+		cfg.HostInfo = hostInfo
+		_, err := New(cfg)
+		require.Error(err, "New should reject an invalid restart backoff configuration")
+	}
+
+	const (
+		initialInterval = 100 * time.Millisecond
+		maxInterval     = time.Second
+		factor          = 0.25
+	)
+	p, err := New(Config{
+		HostInfo:                   hostInfo,
+		RestartInitialInterval:     initialInterval,
+		RestartMaxInterval:         maxInterval,
+		RestartRandomizationFactor: factor,
+	})
+	require.NoError(err, "New")
+	cfg := p.(*provisioner).cfg
+
+	boff := cfg.newRestartBackOff()
+	interval := initialInterval
+	for attempt := 0; attempt < 20; attempt++ {
+		delta := time.Duration(factor * float64(interval))
+		next := boff.NextBackOff()
+		require.GreaterOrEqual(next, interval-delta, "restart interval should be within jittered bounds")
+		require.LessOrEqual(next, interval+delta, "restart interval should be within jittered bounds")
+
+		interval = min(time.Duration(float64(interval)*boff.Multiplier), maxInterval)
+	}
+}
+
+func TestBindRO(t *testing.T) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	dataDir := filepath.Join(dir, "runtime-data")
+	params := filepath.Join(dataDir, "params")
+	require.NoError(os.Mkdir(dataDir, 0o700), "Mkdir")
+	require.NoError(os.WriteFile(params, []byte("params"), 0o600), "WriteFile")
+	require.NoError(os.Mkdir(filepath.Join(dir, "runtime-data-other"), 0o700), "Mkdir")
+
+	// Symlinks within the allowed prefix may only point to paths within the prefix.
+	require.NoError(os.Symlink(params, filepath.Join(dataDir, "params-link")), "Symlink")
+	require.NoError(os.Symlink("/etc", filepath.Join(dataDir, "etc-link")), "Symlink")
+	require.NoError(os.Symlink("../runtime-data-other", filepath.Join(dataDir, "other-link")), "Symlink")
+
+	p, err := New(Config{
+		HostInfo:              newTestHostInfo(),
+		AllowedBindROPrefixes: []string{dataDir},
+	})
+	require.NoError(err, "New")
+	provCfg := p.(*provisioner).cfg
+
+	getConfig := func(bindRO map[string]string) (process.Config, error) {
+		var id common.Namespace
+		return provCfg.getSandboxConfig(host.Config{
+			Bundle: &host.RuntimeBundle{
+				Bundle: &bundle.Bundle{
+					Manifest: &bundle.Manifest{ID: id},
+				},
+				Path: "/runtime",
+			},
+			BindRO: bindRO,
+		}, "/tmp/host.sock", t.TempDir())
+	}
+
+	// No bind mounts by default.
+	cfg, err := getConfig(nil)
+	require.NoError(err, "GetSandboxConfig")
+	require.Empty(cfg.BindRO, "no bind mounts should be configured by default")
+
+	// Allowed bind mounts should be passed to the process configuration.
+	cfg, err = getConfig(map[string]string{
+		params: "/params",
+	})
+	require.NoError(err, "GetSandboxConfig")
+	require.Equal(map[string]string{params: "/params"}, cfg.BindRO)
+
+	// Symlinks should be resolved to their targets.
+	cfg, err = getConfig(map[string]string{
+		filepath.Join(dataDir, "params-link"): "/params",
+	})
+	require.NoError(err, "GetSandboxConfig")
+	require.Equal(map[string]string{params: "/params"}, cfg.BindRO)
+
+	// Disallowed bind mounts should be rejected.
+	for _, bindRO := range []map[string]string{
+		{"/etc": "/etc"},
+		{filepath.Join(dir, "runtime-data-other"): "/params"},
+		{filepath.Join(dataDir, "..", "..", "etc"): "/etc"},
+		{filepath.Join(dataDir, "etc-link"): "/etc"},
+		{filepath.Join(dataDir, "etc-link", "passwd"): "/passwd"},
+		{filepath.Join(dataDir, "other-link"): "/params"},
+		{filepath.Join(dataDir, "does-not-exist"): "/params"},
+		{"srv/runtime-data/params": "/params"},
+		{params: "params"},
+		{params: bindHostSocketPath},
+	} {
+		_, err = getConfig(bindRO)
+		require.Error(err, "GetSandboxConfig should reject %v", bindRO)
+	}
+}
+
+func TestCustomSandboxConfig(t *testing.T) {
+	require := require.New(t)
+
+	dataDir := t.TempDir()
+	params := filepath.Join(dataDir, "params")
+	require.NoError(os.WriteFile(params, []byte("params"), 0o600), "WriteFile")
+
+	// Provisioners with a custom sandbox configuration (e.g., a loader) should still get the
+	// extra environment variables and read-only bind mounts requested by the runtime.
+	p, err := New(Config{
+		HostInfo:              newTestHostInfo(),
+		AllowedBindROPrefixes: []string{dataDir},
+		GetSandboxConfig: func(_ host.Config, socketPath, _ string) (process.Config, error) {
+			return process.Config{
+				Path: "/loader",
+				Env: map[string]string{
+					envWorkerHost: socketPath,
+					"LOADER_VAR":  "loader",
+				},
+				BindRW: map[string]string{
+					"/tmp/runtime.sgxs": "/runtime.sgxs",
+				},
+				BindData: map[string]io.Reader{
+					"/loader.cfg": bytes.NewReader(nil),
+				},
+			}, nil
+		},
+	})
+	require.NoError(err, "New")
+	provCfg := p.(*provisioner).cfg
+
+	getConfig := func(extraEnv, bindRO map[string]string) (process.Config, error) {
+		var id common.Namespace
+		return provCfg.getSandboxConfig(host.Config{
+			Bundle: &host.RuntimeBundle{
+				Bundle: &bundle.Bundle{
+					Manifest: &bundle.Manifest{ID: id},
+				},
+				Path: "/runtime",
+			},
+			ExtraEnv: extraEnv,
+			BindRO:   bindRO,
+		}, "/tmp/host.sock", t.TempDir())
+	}
+
+	cfg, err := getConfig(map[string]string{"TEST_VAR": "test"}, map[string]string{params: "/params"})
+	require.NoError(err, "getSandboxConfig")
+	require.Equal(map[string]string{
+		envWorkerHost: "/tmp/host.sock",
+		"LOADER_VAR":  "loader",
+		"TEST_VAR":    "test",
+	}, cfg.Env)
+	require.Equal(map[string]string{params: "/params"}, cfg.BindRO)
+	require.Equal(map[string]string{"/tmp/runtime.sgxs": "/runtime.sgxs"}, cfg.BindRW)
+
+	// Environment variables and mounts configured by the provisioner cannot be overridden.
+	for _, tc := range []struct {
+		extraEnv map[string]string
+		bindRO   map[string]string
+	}{
+		{extraEnv: map[string]string{"LOADER_VAR": "evil"}},
+		{extraEnv: map[string]string{envWorkerHost: "/tmp/evil.sock"}},
+		{bindRO: map[string]string{params: "/runtime.sgxs"}},
+		{bindRO: map[string]string{params: "/loader.cfg/"}},
+	} {
+		_, err = getConfig(tc.extraEnv, tc.bindRO)
+		require.ErrorContains(err, "reserved", "getSandboxConfig should reject %v %v", tc.extraEnv, tc.bindRO)
+	}
+}
+
+func TestStderrTail(t *testing.T) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	script := filepath.Join(dir, "runtime.sh")
+	err := os.WriteFile(script, []byte("#!/bin/sh\necho first >&2\necho second >&2\necho third >&2\nexit 1\n"), 0o700) // nolint: gosec
+	require.NoError(err, "WriteFile")
+
+	p, err := New(Config{
+		HostInfo:          newTestHostInfo(),
+		StderrTailLines:   2,
+		InsecureNoSandbox: true,
+	})
+	require.NoError(err, "New")
+
+	var id common.Namespace
+	r, err := p.NewRuntime(host.Config{
+		Bundle: &host.RuntimeBundle{
+			Bundle: &bundle.Bundle{
+				Manifest: &bundle.Manifest{ID: id},
+			},
+			Path: script,
+		},
+	})
+	require.NoError(err, "NewRuntime")
+
+	evCh, evSub := r.WatchEvents()
+	defer evSub.Close()
+
+	r.Start()
+	defer r.Stop()
+
+	// The script exits without connecting, so the runtime fails to start.
+	select {
+	case ev := <-evCh:
+		require.NotNil(ev.FailedToStart, "runtime should fail to start")
+		require.Equal([]string{"second", "third"}, ev.FailedToStart.Stderr, "last stderr lines should be attached")
+	case <-time.After(5 * time.Second):
+		t.Fatalf("runtime did not fail to start in time")
+	}
+
+	// Incomplete lines should be included and overly long lines truncated.
+	tail := &stderrTail{maxLines: 2}
+	_, _ = tail.Write([]byte("one\ntw"))
+	_, _ = tail.Write([]byte("o\nthr"))
+	require.Equal([]string{"two", "thr"}, tail.Lines())
+	_, _ = tail.Write(bytes.Repeat([]byte("x"), 2*maxStderrLineSize))
+	lines := tail.Lines()
+	require.Len(lines, 2)
+	require.Len(lines[1], maxStderrLineSize, "long lines should be truncated")
+
+	tail.Reset()
+	require.Nil(tail.Lines(), "no lines should be captured after reset")
+}
+
+func TestAbortAfterStop(t *testing.T) {
+	require := require.New(t)
+
+	r, _ := newTestSandboxedRuntime(t)
+
+	evCh, evSub := r.WatchEvents()
+	defer evSub.Close()
+
+	r.Start()
+	r.Stop()
+	for ev := range evCh {
+		if ev.Stopped != nil {
+			break
+		}
+	}
+
+	// Aborts should fail immediately instead of waiting for the context to expire.
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- r.Abort(context.Background(), false)
+	}()
+
+	select {
+	case err := <-errCh:
+		require.ErrorIs(err, errRuntimeStopped, "Abort should fail after the runtime has been stopped")
+	case <-time.After(time.Second):
+		t.Fatalf("Abort should not block after the runtime has been stopped")
+	}
+}
+
+// standbyReady returns true iff the given runtime has a warm standby process ready.
+func standbyReady(rt host.Runtime) bool {
+	r := rt.(*sandboxedRuntime)
+	r.RLock()
+	defer r.RUnlock()
+	return r.standbyReady
+}
+
+func TestWarmStandby(t *testing.T) {
+	require := require.New(t)
+
+	id := common.NewTestNamespaceFromSeed([]byte("sandbox warm standby test"), 0)
+	rtVersion := version.Version{Major: 1}
+
+	_, err := New(Config{
+		HostInfo:             &protocol.HostInfo{},
+		PersistentRuntimeDir: t.TempDir(),
+		WarmStandby:          true,
+	})
+	require.Error(err, "New should reject warm standby with a persistent runtime directory")
+
+	// Only the first two processes (the primary and the standby) initialize, the rest block until
+	// the test is done.
+	releaseCh := make(chan struct{})
+	defer close(releaseCh)
+	cfg, fr := newFakeRuntimeConfig(t, id, rtVersion)
+	fr.beforeConnect = func(connected int) {
+		if connected >= 2 {
+			<-releaseCh
+		}
+	}
+	cfg.WarmStandby = true
+	p, err := New(cfg)
+	require.NoError(err, "New")
+
+	r, err := p.NewRuntime(host.Config{
+		Bundle: &host.RuntimeBundle{
+			Bundle: &bundle.Bundle{
+				Manifest: &bundle.Manifest{ID: id, Version: rtVersion},
+			},
+		},
+	})
+	require.NoError(err, "NewRuntime")
+
+	evCh, evSub := r.WatchEvents()
+	defer evSub.Close()
+
+	r.Start()
+	defer r.Stop()
+
+	recvEvent := func(timeout time.Duration) *host.Event {
+		select {
+		case ev := <-evCh:
+			return ev
+		case <-time.After(timeout):
+			t.Fatalf("failed to receive runtime event")
+			return nil
+		}
+	}
+
+	require.NotNil(recvEvent(5*time.Second).Started, "runtime should start")
+	pi, err := r.GetProcessInfo()
+	require.NoError(err, "GetProcessInfo")
+
+	// Wait for the standby to be initialized.
+	require.Eventually(func() bool {
+		return standbyReady(r)
+	}, 5*time.Second, 10*time.Millisecond, "standby should be initialized")
+	require.Equal(2, fr.numConnected(), "only the primary and the standby should be spawned")
+
+	// Kill the primary, the standby should be swapped in without waiting for initialization.
+	proc, err := os.FindProcess(pi.PID)
+	require.NoError(err, "FindProcess")
+	require.NoError(proc.Kill(), "Kill")
+
+	require.NotNil(recvEvent(time.Second).Stopped, "primary should stop")
+	require.NotNil(recvEvent(time.Second).Started, "standby should be swapped in")
+
+	newPi, err := r.GetProcessInfo()
+	require.NoError(err, "GetProcessInfo")
+	require.NotEqual(pi.PID, newPi.PID, "standby process should be active")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err = r.Call(ctx, &protocol.Body{RuntimePingRequest: &protocol.Empty{}})
+	require.NoError(err, "Call")
+}
+
+func TestWarmStandbyStop(t *testing.T) {
+	require := require.New(t)
+
+	id := common.NewTestNamespaceFromSeed([]byte("sandbox warm standby stop test"), 0)
+	rtVersion := version.Version{Major: 1}
+
+	// Only the primary connects to the host, the standby never does.
+	var spawned atomic.Int32
+	cfg, _ := newFakeRuntimeConfig(t, id, rtVersion)
+	getSandboxConfig := cfg.GetSandboxConfig
+	cfg.GetSandboxConfig = func(hostCfg host.Config, socketPath, runtimeDir string) (process.Config, error) {
+		if spawned.Add(1) > 1 {
+			return process.Config{
+				Path:   "/bin/sleep",
+				Args:   []string{"60"},
+				Stdout: io.Discard,
+				Stderr: io.Discard,
+			}, nil
+		}
+		return getSandboxConfig(hostCfg, socketPath, runtimeDir)
+	}
+	cfg.RuntimeConnectTimeout = time.Minute
+	cfg.WarmStandby = true
+	p, err := New(cfg)
+	require.NoError(err, "New")
+
+	r, err := p.NewRuntime(host.Config{
+		Bundle: &host.RuntimeBundle{
+			Bundle: &bundle.Bundle{
+				Manifest: &bundle.Manifest{ID: id, Version: rtVersion},
+			},
+		},
+	})
+	require.NoError(err, "NewRuntime")
+
+	evCh, evSub := r.WatchEvents()
+	defer evSub.Close()
+
+	r.Start()
+
+	select {
+	case ev := <-evCh:
+		require.NotNil(ev.Started, "runtime should start")
+	case <-time.After(5 * time.Second):
+		t.Fatalf("failed to receive runtime started event")
+	}
+	require.Eventually(func() bool {
+		return spawned.Load() == 2
+	}, 5*time.Second, 10*time.Millisecond, "standby should be spawned")
+
+	// Stopping the runtime should not wait for the standby to connect.
+	r.Stop()
+	select {
+	case ev := <-evCh:
+		require.NotNil(ev.Stopped, "runtime should stop")
+	case <-time.After(5 * time.Second):
+		t.Fatalf("runtime did not stop while waiting for the standby to connect")
+	}
+}
+
+func TestWarmStandbyIsolation(t *testing.T) {
+	require := require.New(t)
+
+	id := common.NewTestNamespaceFromSeed([]byte("sandbox warm standby isolation test"), 0)
+	rtVersion := version.Version{Major: 1}
+
+	var (
+		lock sync.Mutex
+		hps  []*HostInitializerParams
+	)
+	getHostParams := func() []*HostInitializerParams {
+		lock.Lock()
+		defer lock.Unlock()
+		return append([]*HostInitializerParams{}, hps...)
+	}
+	testCapabilityTEE := func(n byte) *node.CapabilityTEE {
+		capabilityTEE := &node.CapabilityTEE{Hardware: node.TEEHardwareIntelSGX}
+		capabilityTEE.RAK[0] = n
+		return capabilityTEE
+	}
+
+	// Each process gets a distinct RAK.
+	cfg, _ := newFakeRuntimeConfig(t, id, rtVersion)
+	cfg.HostInitializer = func(_ context.Context, hp *HostInitializerParams) (*host.StartedEvent, error) {
+		lock.Lock()
+		defer lock.Unlock()
+		hps = append(hps, hp)
+
+		return &host.StartedEvent{
+			Version:       hp.Version,
+			CapabilityTEE: testCapabilityTEE(byte(len(hps))),
+		}, nil
+	}
+	cfg.WarmStandby = true
+	p, err := New(cfg)
+	require.NoError(err, "New")
+
+	r, err := p.NewRuntime(host.Config{
+		Bundle: &host.RuntimeBundle{
+			Bundle: &bundle.Bundle{
+				Manifest: &bundle.Manifest{ID: id, Version: rtVersion},
+			},
+		},
+	})
+	require.NoError(err, "NewRuntime")
+
+	evCh, evSub := r.WatchEvents()
+	defer evSub.Close()
+
+	r.Start()
+	defer r.Stop()
+
+	recvEvent := func(timeout time.Duration) *host.Event {
+		select {
+		case ev := <-evCh:
+			return ev
+		case <-time.After(timeout):
+			t.Fatalf("failed to receive runtime event")
+			return nil
+		}
+	}
+
+	require.NotNil(recvEvent(5*time.Second).Started, "runtime should start")
+	require.Eventually(func() bool {
+		return standbyReady(r)
+	}, 5*time.Second, 10*time.Millisecond, "standby should be initialized")
+	active, standby := getHostParams()[0], getHostParams()[1]
+
+	// Updates emitted by the standby must not affect the active runtime.
+	standby.Runtime.(host.RuntimeEventEmitter).EmitEvent(&host.Event{Updated: &host.UpdatedEvent{
+		Version:       rtVersion,
+		CapabilityTEE: testCapabilityTEE(42),
+	}})
+	capabilityTEE, err := r.GetCapabilityTEE()
+	require.NoError(err, "GetCapabilityTEE")
+	require.EqualValues(testCapabilityTEE(1), capabilityTEE, "standby updates should not change the active CapabilityTEE")
+	select {
+	case ev := <-evCh:
+		t.Fatalf("unexpected event: %+v", ev)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	// Update requests must only be delivered to the active process.
+	r.UpdateCapabilityTEE()
+	select {
+	case <-active.NotifyUpdateCapabilityTEE:
+	case <-time.After(time.Second):
+		t.Fatalf("active process should be notified to update CapabilityTEE")
+	}
+	select {
+	case <-standby.NotifyUpdateCapabilityTEE:
+		t.Fatalf("standby process should not be notified to update CapabilityTEE")
+	default:
+	}
+
+	// Kill the primary, the standby should be swapped in with its latest CapabilityTEE.
+	pi, err := r.GetProcessInfo()
+	require.NoError(err, "GetProcessInfo")
+	proc, err := os.FindProcess(pi.PID)
+	require.NoError(err, "FindProcess")
+	require.NoError(proc.Kill(), "Kill")
+
+	require.NotNil(recvEvent(time.Second).Stopped, "primary should stop")
+	ev := recvEvent(time.Second)
+	require.NotNil(ev.Started, "standby should be swapped in")
+	require.EqualValues(testCapabilityTEE(42), ev.Started.CapabilityTEE, "standby should be activated with its latest CapabilityTEE")
+
+	// Updates emitted by the terminated primary must be ignored.
+	active.Runtime.(host.RuntimeEventEmitter).EmitEvent(&host.Event{Updated: &host.UpdatedEvent{
+		Version:       rtVersion,
+		CapabilityTEE: testCapabilityTEE(1),
+	}})
+	capabilityTEE, err = r.GetCapabilityTEE()
+	require.NoError(err, "GetCapabilityTEE")
+	require.EqualValues(testCapabilityTEE(42), capabilityTEE, "stale process updates should be ignored")
+}
+
+func TestResourceLimitsRequireCgroupParent(t *testing.T) {
+	require := require.New(t)
+
+	_, err := New(Config{
+		HostInfo:         &protocol.HostInfo{},
+		MemoryLimitBytes: 256 * 1024 * 1024,
+	})
+	require.Error(err, "New should reject resource limits without a parent cgroup")
+
+	_, err = New(Config{
+		HostInfo:         &protocol.HostInfo{},
+		MemoryLimitBytes: 256 * 1024 * 1024,
+		CgroupParent:     "/sys/fs/cgroup/oasis-runtimes",
+	})
+	require.NoError(err, "New")
+}
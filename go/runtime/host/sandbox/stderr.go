@@ -0,0 +1,86 @@
+package sandbox
+
+import (
+	"bytes"
+	"sync"
+)
+
+// maxStderrLineSize is the maximum size of a single captured stderr line. Longer lines are
+// truncated.
+const maxStderrLineSize = 4096
+
+// stderrTail is a writer that keeps the last lines written to it.
+type stderrTail struct {
+	sync.Mutex
+
+	maxLines int
+	lines    []string
+	partial  []byte
+}
+
+// Write implements io.Writer.
+func (t *stderrTail) Write(p []byte) (int, error) {
+	t.Lock()
+	defer t.Unlock()
+
+	data := p
+	for len(data) > 0 {
+		idx := bytes.IndexByte(data, '\n')
+		if idx < 0 {
+			t.appendPartialLocked(data)
+			break
+		}
+
+		t.appendPartialLocked(data[:idx])
+		t.pushLineLocked(string(t.partial))
+		t.partial = t.partial[:0]
+		data = data[idx+1:]
+	}
+
+	return len(p), nil
+}
+
+func (t *stderrTail) appendPartialLocked(data []byte) {
+	if n := maxStderrLineSize - len(t.partial); n < len(data) {
+		data = data[:max(n, 0)]
+	}
+	t.partial = append(t.partial, data...)
+}
+
+func (t *stderrTail) pushLineLocked(line string) {
+	if t.maxLines <= 0 {
+		return
+	}
+	if len(t.lines) >= t.maxLines {
+		t.lines = t.lines[1:]
+	}
+	t.lines = append(t.lines, line)
+}
+
+// Lines returns the last captured lines, including any incomplete last line.
+func (t *stderrTail) Lines() []string {
+	t.Lock()
+	defer t.Unlock()
+
+	lines := make([]string, 0, len(t.lines)+1)
+	lines = append(lines, t.lines...)
+	if len(t.partial) > 0 {
+		lines = append(lines, string(t.partial))
+	}
+	if len(lines) > t.maxLines {
+		lines = lines[len(lines)-t.maxLines:]
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	return lines
+}
+
+// Reset discards all captured lines.
+func (t *stderrTail) Reset() {
+	t.Lock()
+	defer t.Unlock()
+
+	t.lines = nil
+	t.partial = nil
+}
@@ -66,6 +66,7 @@ func TestProvisioner(
 	defaultTestCases := []TestCase{
 		{"Basic", testBasic},
 		{"Restart", testRestart},
+		{"ControlledRestart", testControlledRestart},
 	}
 	testCases := append(defaultTestCases, extraTests...)
 
@@ -120,6 +121,13 @@ func testBasic(t *testing.T, cfg host.Config, p host.Provisioner) {
 		t.Fatalf("Failed to receive start event")
 	}
 
+	// Process information should be available.
+	pi, err := r.GetProcessInfo()
+	require.NoError(err, "GetProcessInfo")
+	require.NotZero(pi.PID, "process identifier should be set")
+	require.False(pi.StartTime.IsZero(), "process start time should be set")
+	require.EqualValues(0, pi.RestartCount, "runtime should not have been restarted")
+
 	// Test with a simple ping request.
 	ctx, cancel := context.WithTimeout(context.Background(), recvTimeout)
 	defer cancel()
@@ -166,6 +174,9 @@ func testRestart(t *testing.T, cfg host.Config, p host.Provisioner) {
 		t.Fatalf("Failed to receive event")
 	}
 
+	pi, err := r.GetProcessInfo()
+	require.NoError(err, "GetProcessInfo")
+
 	// Trigger a force abort (should restart the runtime).
 	err = r.Abort(context.Background(), true)
 	require.NoError(err, "Abort(force=true)")
@@ -186,6 +197,13 @@ func testRestart(t *testing.T, cfg host.Config, p host.Provisioner) {
 		t.Fatalf("Failed to receive event")
 	}
 
+	// Process information should reflect the restart.
+	restartedPi, err := r.GetProcessInfo()
+	require.NoError(err, "GetProcessInfo")
+	require.NotEqual(pi.PID, restartedPi.PID, "restarted runtime should run in a new process")
+	require.True(restartedPi.StartTime.After(pi.StartTime), "restarted runtime should have a later start time")
+	require.EqualValues(pi.RestartCount+1, restartedPi.RestartCount, "restart count should be incremented")
+
 	// Trigger a non-force abort (runtime should not be restarted).
 	err = r.Abort(context.Background(), false)
 	require.NoError(err, "Abort(force=false)")
@@ -212,3 +230,51 @@ func testRestart(t *testing.T, cfg host.Config, p host.Provisioner) {
 	case <-time.After(recvAbortTimeout):
 	}
 }
+
+func testControlledRestart(t *testing.T, cfg host.Config, p host.Provisioner) {
+	require := require.New(t)
+
+	r, err := p.NewRuntime(cfg)
+	require.NoError(err, "NewRuntime")
+	r.Start()
+	defer r.Stop()
+
+	evCh, sub := r.WatchEvents()
+	defer sub.Close()
+
+	// Wait for a successful start event.
+	select {
+	case ev := <-evCh:
+		require.NotNil(ev.Started, "should have received a successful start event")
+	case <-time.After(recvTimeout):
+		t.Fatalf("Failed to receive event")
+	}
+
+	pi, err := r.GetProcessInfo()
+	require.NoError(err, "GetProcessInfo")
+
+	// Restart the runtime, which should block until the runtime is started again.
+	ctx, cancel := context.WithTimeout(context.Background(), recvTimeout)
+	defer cancel()
+	err = r.Restart(ctx)
+	require.NoError(err, "Restart")
+
+	// Wait for a stop event followed by a successful start event.
+	select {
+	case ev := <-evCh:
+		require.NotNil(ev.Stopped, "should have received a stop event")
+	case <-time.After(recvTimeout):
+		t.Fatalf("Failed to receive stop event")
+	}
+	select {
+	case ev := <-evCh:
+		require.NotNil(ev.Started, "should have received a successful start event")
+	case <-time.After(recvTimeout):
+		t.Fatalf("Failed to receive event")
+	}
+
+	// Process information should reflect the restart.
+	restartedPi, err := r.GetProcessInfo()
+	require.NoError(err, "GetProcessInfo")
+	require.NotEqual(pi.PID, restartedPi.PID, "restarted runtime should run in a new process")
+}
@@ -0,0 +1,55 @@
+package api
+
+import "errors"
+
+var (
+	// ErrAbortRoundEnded is the abort reason used when the round ended before the batch was
+	// processed (e.g., a newer block has been seen).
+	ErrAbortRoundEnded = errors.New("worker: round ended")
+	// ErrAbortRankOutOfBounds is the abort reason used when the scheduler rank of the batch
+	// is no longer within the accepted bounds.
+	ErrAbortRankOutOfBounds = errors.New("worker: rank out of bounds")
+	// ErrAbortRuntimeAborted is the abort reason used when the runtime failed to process
+	// the batch.
+	ErrAbortRuntimeAborted = errors.New("worker: runtime aborted batch processing")
+	// ErrAbortInvalidResults is the abort reason used when the runtime returned malformed
+	// or incorrectly signed results.
+	ErrAbortInvalidResults = errors.New("worker: invalid runtime results")
+	// ErrAbortStorageFailed is the abort reason used when the results could not be committed
+	// to storage.
+	ErrAbortStorageFailed = errors.New("worker: storage commit failed")
+	// ErrAbortSubmitFailed is the abort reason used when the commitment could not be submitted.
+	ErrAbortSubmitFailed = errors.New("worker: commitment submission failed")
+	// ErrAbortOperator is the abort reason used when the node operator requested the batch
+	// to be aborted.
+	ErrAbortOperator = errors.New("worker: aborted by operator")
+)
+
+// AbortReasonOther is the classification of abort reasons that are not known.
+const AbortReasonOther = "other"
+
+var abortReasons = []struct {
+	err    error
+	reason string
+}{
+	{ErrAbortRoundEnded, "round_ended"},
+	{ErrAbortRankOutOfBounds, "rank_out_of_bounds"},
+	{ErrAbortRuntimeAborted, "runtime_aborted"},
+	{ErrAbortInvalidResults, "invalid_results"},
+	{ErrAbortStorageFailed, "storage_failed"},
+	{ErrAbortSubmitFailed, "submit_failed"},
+	{ErrAbortOperator, "operator"},
+}
+
+// ClassifyAbortReason maps an abort reason to a stable string suitable for use in metric
+// labels and logs.
+//
+// Unknown reasons are classified as AbortReasonOther.
+func ClassifyAbortReason(err error) string {
+	for _, r := range abortReasons {
+		if errors.Is(err, r.err) {
+			return r.reason
+		}
+	}
+	return AbortReasonOther
+}
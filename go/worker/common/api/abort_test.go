@@ -0,0 +1,31 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifyAbortReason(t *testing.T) {
+	require := require.New(t)
+
+	for _, tc := range []struct {
+		err    error
+		reason string
+	}{
+		{ErrAbortRoundEnded, "round_ended"},
+		{ErrAbortRankOutOfBounds, "rank_out_of_bounds"},
+		{ErrAbortRuntimeAborted, "runtime_aborted"},
+		{ErrAbortInvalidResults, "invalid_results"},
+		{ErrAbortStorageFailed, "storage_failed"},
+		{ErrAbortSubmitFailed, "submit_failed"},
+		{ErrAbortOperator, "operator"},
+		{fmt.Errorf("%w: context canceled", ErrAbortSubmitFailed), "submit_failed"},
+		{errors.New("unknown error"), AbortReasonOther},
+		{nil, AbortReasonOther},
+	} {
+		require.Equal(tc.reason, ClassifyAbortReason(tc.err), "ClassifyAbortReason(%v)", tc.err)
+	}
+}
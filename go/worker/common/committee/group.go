@@ -77,6 +77,16 @@ type EpochSnapshot struct {
 	nodes nodes.VersionedNodeDescriptorWatcher
 }
 
+// NewMockEpochSnapshot creates a new epoch snapshot in which the local node is a member of the
+// given executor committee. It is only meant to be used in tests.
+func NewMockEpochSnapshot(epochNumber beacon.EpochTime, runtime *registry.Runtime, executorCommittee *CommitteeInfo) *EpochSnapshot {
+	return &EpochSnapshot{
+		epochNumber:       epochNumber,
+		runtime:           runtime,
+		executorCommittee: executorCommittee,
+	}
+}
+
 // IsValid checks whether the given epoch snapshot is valid (represents an actual epoch).
 func (e *EpochSnapshot) IsValid() bool {
 	return e.identity != nil
@@ -590,7 +590,7 @@ func (n *Node) handleRuntimeHostEventLocked(ev *host.Event) {
 	switch {
 	case ev.Started != nil:
 		atomic.StoreUint32(&n.hostedRuntimeProvisioned, 1)
-	case ev.FailedToStart != nil, ev.Stopped != nil:
+	case ev.FailedToStart != nil, ev.Stopped != nil && !ev.Stopped.Idle:
 		atomic.StoreUint32(&n.hostedRuntimeProvisioned, 0)
 	}
 
@@ -4,9 +4,22 @@ import (
 	"context"
 
 	"github.com/oasisprotocol/oasis-core/go/common/crash"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
 	"github.com/oasisprotocol/oasis-core/go/roothash/api/commitment"
 )
 
+const (
+	// discrepancyOutcomeAgreed is the outcome when the finalized results match the results
+	// computed by the node.
+	discrepancyOutcomeAgreed = "agreed"
+	// discrepancyOutcomeDisagreed is the outcome when the finalized results differ from the
+	// results computed by the node.
+	discrepancyOutcomeDisagreed = "disagreed"
+	// discrepancyOutcomeTimedOut is the outcome when the round failed without resolving
+	// the discrepancy.
+	discrepancyOutcomeTimedOut = "timed_out"
+)
+
 type discrepancyEvent struct {
 	height        uint64
 	round         uint64
@@ -103,3 +116,36 @@ func (n *Node) predictDiscrepancy(ctx context.Context, ec *commitment.ExecutorCo
 		authoritative: false,
 	})
 }
+
+// recordDiscrepancyResolution records the outcome of the discrepancy detected in the round that
+// has just been finalized, if any.
+//
+// Outcomes can only be determined for rounds that failed or for which the node has computed its
+// own results.
+func (n *Node) recordDiscrepancyResolution() {
+	hdr := n.blockInfo.RuntimeBlock.Header
+	if n.discrepancy == nil || n.discrepancy.round != hdr.Round {
+		return
+	}
+
+	var outcome string
+	switch {
+	case hdr.HeaderType == block.RoundFailed:
+		outcome = discrepancyOutcomeTimedOut
+	case hdr.HeaderType != block.Normal, n.proposedBatch == nil:
+		return
+	case hdr.IORoot.Equal(&n.proposedBatch.proposedIORoot):
+		outcome = discrepancyOutcomeAgreed
+	default:
+		outcome = discrepancyOutcomeDisagreed
+	}
+
+	n.logger.Info("execution discrepancy resolved",
+		"round", hdr.Round,
+		"outcome", outcome,
+	)
+
+	labels := n.getMetricLabels()
+	labels["outcome"] = outcome
+	discrepancyResolutionCount.With(labels).Inc()
+}
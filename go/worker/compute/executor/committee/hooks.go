@@ -21,6 +21,16 @@ func (n *Node) HandleNewBlockEarlyLocked(*runtime.BlockInfo) {
 // HandleNewBlockLocked implements NodeHooks.
 // Guarded by n.commonNode.CrossNode.
 func (n *Node) HandleNewBlockLocked(bi *runtime.BlockInfo) {
+	// Ignore blocks which do not advance the round, as restarting the round worker would
+	// needlessly cancel any in-flight work for the current round.
+	if lbi := n.lastBlockInfo; lbi != nil && lbi.RuntimeBlock.Header.Round == bi.RuntimeBlock.Header.Round {
+		n.logger.Debug("ignoring block for the current round",
+			"round", bi.RuntimeBlock.Header.Round,
+		)
+		return
+	}
+	n.lastBlockInfo = bi
+
 	// Drop blocks if the worker falls behind.
 	select {
 	case <-n.blockInfoCh:
@@ -23,11 +23,32 @@ var (
 		},
 		[]string{"runtime"},
 	)
+	discrepancyResolutionCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_worker_execution_discrepancy_resolution",
+			Help: "Number of resolved execute discrepancies by outcome.",
+		},
+		[]string{"runtime", "outcome"},
+	)
+	backupWorkerActivationCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_worker_backup_worker_activation_count",
+			Help: "Number of times a backup worker started processing a batch due to a discrepancy.",
+		},
+		[]string{"runtime"},
+	)
 	abortedBatchCount = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "oasis_worker_aborted_batch_count",
 			Help: "Number of aborted batches.",
 		},
+		[]string{"runtime", "reason"},
+	)
+	inflightBatchGoroutines = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "oasis_worker_inflight_batch_goroutines",
+			Help: "Number of in-flight batch processing goroutines.",
+		},
 		[]string{"runtime"},
 	)
 	storageCommitLatency = prometheus.NewSummaryVec(
@@ -61,7 +82,10 @@ var (
 	nodeCollectors = []prometheus.Collector{
 		processedEventCount,
 		discrepancyDetectedCount,
+		discrepancyResolutionCount,
+		backupWorkerActivationCount,
 		abortedBatchCount,
+		inflightBatchGoroutines,
 		storageCommitLatency,
 		batchProcessingTime,
 		batchRuntimeProcessingTime,
@@ -8,16 +8,20 @@ import (
 	"sync"
 	"time"
 
+	"github.com/eapache/channels"
 	"golang.org/x/exp/maps"
 
 	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	"github.com/oasisprotocol/oasis-core/go/common/crash"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
 	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
 	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
 	p2p "github.com/oasisprotocol/oasis-core/go/p2p/api"
 	p2pProtocol "github.com/oasisprotocol/oasis-core/go/p2p/protocol"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
 	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
 	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
 	"github.com/oasisprotocol/oasis-core/go/roothash/api/commitment"
@@ -29,6 +33,7 @@ import (
 	scheduler "github.com/oasisprotocol/oasis-core/go/scheduler/api"
 	storage "github.com/oasisprotocol/oasis-core/go/storage/api"
 	commonWorker "github.com/oasisprotocol/oasis-core/go/worker/common"
+	commonAPI "github.com/oasisprotocol/oasis-core/go/worker/common/api"
 	"github.com/oasisprotocol/oasis-core/go/worker/common/committee"
 	"github.com/oasisprotocol/oasis-core/go/worker/common/p2p/txsync"
 	"github.com/oasisprotocol/oasis-core/go/worker/registration"
@@ -36,6 +41,8 @@ import (
 
 var (
 	errMsgFromNonTxnSched = fmt.Errorf("executor: received txn scheduler dispatch msg from non-txn scheduler")
+	errInvalidRakSig      = fmt.Errorf("executor: invalid RAK signature")
+	errInvalidHeader      = fmt.Errorf("executor: invalid compute results header")
 
 	// abortTimeout is the duration to wait for the runtime to abort.
 	abortTimeout = 5 * time.Second
@@ -72,16 +79,25 @@ type Node struct { // nolint: maligned
 	// Global, used by every round worker.
 
 	state            NodeState
+	stateLock        sync.RWMutex
 	stateTransitions *pubsub.Broker
 	proposals        *proposalQueue
 	committee        *scheduler.Committee
 	commitPool       *commitment.Pool
 
 	blockInfoCh      chan *runtime.BlockInfo
+	lastBlockInfo    *runtime.BlockInfo // Guarded by n.commonNode.CrossNode.
 	processedBatchCh chan *processedBatch
 	reselectCh       chan struct{}
+	batchWg          sync.WaitGroup
+	abortCh          chan struct{}
 	missingTxCh      chan [][]byte
 
+	// Maximum attestation age cached for the current epoch.
+	// Guarded by n.commonNode.CrossNode.
+	maxAttestationAge      uint64
+	maxAttestationAgeEpoch beacon.EpochTime
+
 	txCh <-chan []*txpool.PendingCheckTransaction
 	ecCh <-chan *commitment.ExecutorCommitment
 	evCh <-chan *roothash.Event
@@ -153,6 +169,21 @@ func (n *Node) WatchStateTransitions() (<-chan NodeState, *pubsub.Subscription)
 	return ch, sub
 }
 
+// WatchStateTransitionsWithCurrent subscribes to the node's state transitions
+// and immediately delivers the current state to the new subscriber.
+func (n *Node) WatchStateTransitionsWithCurrent() (<-chan NodeState, *pubsub.Subscription) {
+	sub := n.stateTransitions.SubscribeEx(-1, func(ch channels.Channel) {
+		n.stateLock.RLock()
+		defer n.stateLock.RUnlock()
+
+		ch.In() <- n.state
+	})
+	typedCh := make(chan NodeState)
+	sub.Unwrap(typedCh)
+
+	return typedCh, sub
+}
+
 func (n *Node) reselect() {
 	select {
 	case n.reselectCh <- struct{}{}:
@@ -161,6 +192,17 @@ func (n *Node) reselect() {
 	}
 }
 
+// ForceAbort requests the round worker to abort the batch that is currently being processed.
+//
+// The request is ignored if the node is not processing a batch at the time it is handled.
+func (n *Node) ForceAbort() {
+	select {
+	case n.abortCh <- struct{}{}:
+	default:
+		// If there's one already queued, we don't need to do anything.
+	}
+}
+
 func (n *Node) transitionState(state NodeState) {
 	n.logger.Info("state transition",
 		"current_state", n.state,
@@ -182,11 +224,23 @@ func (n *Node) transitionState(state NodeState) {
 		panic(fmt.Sprintf("invalid state transition: %s -> %s", n.state, state))
 	}
 
+	n.stateLock.Lock()
 	n.state = state
+	n.stateLock.Unlock()
+
 	n.stateTransitions.Broadcast(state)
 }
 
 func (n *Node) transitionStateToProcessing(ctx context.Context, proposal *commitment.Proposal, rank uint64, batch transaction.RawBatch) {
+	// Nodes that are only backup workers process batches only after a discrepancy was detected.
+	if !n.epoch.IsExecutorWorker() && n.epoch.IsExecutorBackupWorker() {
+		n.logger.Info("backup worker activating and processing batch",
+			"round", proposal.Header.Round,
+			"rank", rank,
+		)
+		backupWorkerActivationCount.With(n.getMetricLabels()).Inc()
+	}
+
 	ctx, cancel := context.WithCancelCause(ctx)
 	done := make(chan struct{})
 
@@ -200,10 +254,9 @@ func (n *Node) transitionStateToProcessing(ctx context.Context, proposal *commit
 
 	// Request the worker host to process a batch. This is done in a separate
 	// goroutine so that the runtime worker can continue processing events.
-	go func() {
-		defer close(done)
+	n.goBatch(done, func() {
 		n.startProcessingBatch(ctx, proposal, rank, batch)
-	}()
+	})
 }
 
 func (n *Node) transitionStateToProcessingFailure(
@@ -291,7 +344,7 @@ func (n *Node) updateState(ctx context.Context, minRank uint64, maxRank uint64,
 	case StateProcessingBatch:
 		if state.rank < minRank || state.rank > maxRank {
 			// Rank ouf ot bounds; stop processing.
-			n.abortBatch(&state)
+			n.abortBatch(&state, commonAPI.ErrAbortRankOutOfBounds)
 			n.transitionState(StateWaitingForBatch{})
 			return
 		}
@@ -429,10 +482,28 @@ func (n *Node) scheduleBatch(ctx context.Context, round uint64, force bool) {
 
 	// Request the worker host to schedule a batch. This is done in a separate
 	// goroutine so that the runtime worker can continue processing events.
-	go func() {
-		defer close(done)
+	n.goBatch(done, func() {
 		n.startSchedulingBatch(ctx, batch)
 		n.commonNode.TxPool.FinishScheduling()
+	})
+}
+
+// goBatch runs the given batch processing function in a separate goroutine and closes the
+// done channel once it returns.
+//
+// All such goroutines are tracked so that the worker can wait for them to finish on exit.
+func (n *Node) goBatch(done chan struct{}, fn func()) {
+	labels := n.getMetricLabels()
+
+	n.batchWg.Add(1)
+	inflightBatchGoroutines.With(labels).Inc()
+
+	go func() {
+		defer close(done)
+		defer n.batchWg.Done()
+		defer inflightBatchGoroutines.With(labels).Dec()
+
+		fn()
 	}()
 }
 
@@ -637,9 +708,10 @@ func (n *Node) startLocalStorageReplication(
 	return ch
 }
 
-func (n *Node) runtimeExecuteTxBatch(
+// newExecuteTxBatchRequest prepares a runtime request for executing the given batch on top of
+// the given block.
+func (n *Node) newExecuteTxBatchRequest(
 	ctx context.Context,
-	rt host.RichRuntime,
 	mode protocol.ExecutionMode,
 	epoch beacon.EpochTime,
 	consensusBlk *consensus.LightBlock,
@@ -648,7 +720,7 @@ func (n *Node) runtimeExecuteTxBatch(
 	roundResults *roothash.RoundResults,
 	inputRoot hash.Hash,
 	inputs transaction.RawBatch,
-) (*protocol.RuntimeExecuteTxBatchResponse, error) {
+) (*protocol.Body, error) {
 	// Ensure block round is synced to storage.
 	n.logger.Debug("ensuring block round is synced", "round", blk.Header.Round)
 	if _, err := n.commonNode.Runtime.History().WaitRoundSynced(ctx, blk.Header.Round); err != nil {
@@ -680,6 +752,25 @@ func (n *Node) runtimeExecuteTxBatch(
 			MaxMessages:    state.Runtime.Executor.MaxMessages,
 		},
 	}
+	return rq, nil
+}
+
+func (n *Node) runtimeExecuteTxBatch(
+	ctx context.Context,
+	rt host.RichRuntime,
+	mode protocol.ExecutionMode,
+	epoch beacon.EpochTime,
+	consensusBlk *consensus.LightBlock,
+	blk *block.Block,
+	state *roothash.RuntimeState,
+	roundResults *roothash.RoundResults,
+	inputRoot hash.Hash,
+	inputs transaction.RawBatch,
+) (*protocol.RuntimeExecuteTxBatchResponse, error) {
+	rq, err := n.newExecuteTxBatchRequest(ctx, mode, epoch, consensusBlk, blk, state, roundResults, inputRoot, inputs)
+	if err != nil {
+		return nil, err
+	}
 	batchSize.With(n.getMetricLabels()).Observe(float64(len(inputs)))
 
 	rtStartTime := time.Now()
@@ -740,6 +831,156 @@ func (n *Node) runtimeExecuteTxBatch(
 	return rsp.RuntimeExecuteTxBatchResponse, nil
 }
 
+// ReplayBatch re-executes the batch with the given I/O root in the given historical round using
+// the currently active runtime version and returns the computed batch without publishing anything.
+//
+// The I/O root need not be the one of the finalized block, so batches from discrepant commitments
+// can be replayed as well, as long as their I/O tree is available in local storage.
+//
+// The batch is executed on a separate runtime instance so that replays cannot interfere with
+// batch processing. It is executed on top of the parent block, using the consensus state at the
+// height at which the parent block was finalized. The inputs and the parent state must still be
+// available in local storage.
+func (n *Node) ReplayBatch(ctx context.Context, round uint64, ioRoot hash.Hash) (*protocol.ComputedBatch, error) {
+	if round == 0 {
+		return nil, fmt.Errorf("executor: cannot replay the genesis round")
+	}
+
+	parent, err := n.commonNode.Runtime.History().GetAnnotatedBlock(ctx, round-1)
+	if err != nil {
+		return nil, fmt.Errorf("executor: failed to fetch parent block for round %d: %w", round, err)
+	}
+	height := parent.Height
+
+	consensusBlk, err := n.commonNode.Consensus.GetLightBlock(ctx, height)
+	if err != nil {
+		return nil, fmt.Errorf("executor: failed to fetch consensus block at height %d: %w", height, err)
+	}
+	epoch, err := n.commonNode.Consensus.Beacon().GetEpoch(ctx, height)
+	if err != nil {
+		return nil, fmt.Errorf("executor: failed to fetch epoch at height %d: %w", height, err)
+	}
+	state, roundResults, err := n.getRtStateAndRoundResults(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fetch the inputs of the original batch.
+	ioTree := transaction.NewTree(n.commonNode.Runtime.Storage(), storage.Root{
+		Namespace: parent.Block.Header.Namespace,
+		Version:   round,
+		Type:      storage.RootTypeIO,
+		Hash:      ioRoot,
+	})
+	defer ioTree.Close()
+
+	inputs, err := ioTree.GetInputBatch(ctx, 0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("executor: failed to fetch inputs for round %d: %w", round, err)
+	}
+
+	// Recompute the input root of the original proposal.
+	emptyRoot := storage.Root{
+		Namespace: parent.Block.Header.Namespace,
+		Version:   round,
+		Type:      storage.RootTypeIO,
+	}
+	emptyRoot.Hash.Empty()
+
+	inputTree := transaction.NewTree(nil, emptyRoot)
+	defer inputTree.Close()
+
+	for idx, tx := range inputs {
+		if err = inputTree.AddTransaction(ctx, transaction.Transaction{Input: tx, BatchOrder: uint32(idx)}, nil); err != nil {
+			return nil, fmt.Errorf("executor: failed to create input tree: %w", err)
+		}
+	}
+	_, inputRoot, err := inputTree.Commit(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("executor: failed to create input tree: %w", err)
+	}
+
+	rq, err := n.newExecuteTxBatchRequest(
+		ctx,
+		protocol.ExecutionModeExecute,
+		epoch,
+		consensusBlk,
+		parent.Block,
+		state,
+		roundResults,
+		inputRoot,
+		inputs,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rt, err := n.provisionReplayRuntime(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rt.Stop()
+
+	n.logger.Info("replaying batch",
+		"round", round,
+		"height", height,
+		"batch_size", len(inputs),
+	)
+
+	rsp, err := rt.Call(ctx, rq)
+	if err != nil {
+		return nil, fmt.Errorf("executor: failed to replay batch: %w", err)
+	}
+	if rsp.RuntimeExecuteTxBatchResponse == nil {
+		return nil, fmt.Errorf("executor: malformed response from runtime")
+	}
+	return &rsp.RuntimeExecuteTxBatchResponse.Batch, nil
+}
+
+// provisionReplayRuntime provisions and starts a new instance of the currently active runtime
+// version, to be used for replaying batches.
+func (n *Node) provisionReplayRuntime(ctx context.Context) (host.Runtime, error) {
+	activeVersion, err := n.commonNode.GetHostedRuntimeActiveVersion()
+	if err != nil {
+		return nil, fmt.Errorf("executor: runtime not available: %w", err)
+	}
+	cfgs, provisioner, err := n.commonNode.Runtime.Host()
+	if err != nil {
+		return nil, fmt.Errorf("executor: failed to get runtime host: %w", err)
+	}
+	cfg, ok := cfgs[*activeVersion]
+	if !ok {
+		return nil, fmt.Errorf("executor: no configuration for runtime version %s", activeVersion)
+	}
+
+	rtCfg := *cfg
+	rtCfg.MessageHandler = n.commonNode.NewRuntimeHostHandler()
+	rt, err := provisioner.NewRuntime(rtCfg)
+	if err != nil {
+		return nil, fmt.Errorf("executor: failed to provision runtime: %w", err)
+	}
+
+	evCh, sub := rt.WatchEvents()
+	defer sub.Close()
+
+	rt.Start()
+	for {
+		select {
+		case ev := <-evCh:
+			switch {
+			case ev.Started != nil:
+				return rt, nil
+			case ev.FailedToStart != nil:
+				rt.Stop()
+				return nil, fmt.Errorf("executor: failed to start runtime: %w", ev.FailedToStart.Error)
+			}
+		case <-ctx.Done():
+			rt.Stop()
+			return nil, ctx.Err()
+		}
+	}
+}
+
 func (n *Node) startProcessingBatch(ctx context.Context, proposal *commitment.Proposal, rank uint64, batch transaction.RawBatch) {
 	// This method runs within its own goroutine and is always stopped before the runtime
 	// worker finishes. Therefore, it is safe to read local round variables (block info, ...).
@@ -793,11 +1034,13 @@ func (n *Node) startProcessingBatch(ctx context.Context, proposal *commitment.Pr
 	}
 }
 
-func (n *Node) abortBatch(state *StateProcessingBatch) {
-	n.logger.Warn("aborting processing batch")
+func (n *Node) abortBatch(state *StateProcessingBatch, reason error) {
+	n.logger.Warn("aborting processing batch",
+		"reason", reason,
+	)
 
 	// Stop processing.
-	state.Cancel(errors.New("batch aborted"))
+	state.Cancel(reason)
 
 	// Discard the result if there was any.
 	select {
@@ -807,7 +1050,48 @@ func (n *Node) abortBatch(state *StateProcessingBatch) {
 
 	crash.Here(crashPointBatchAbortAfter)
 
-	abortedBatchCount.With(n.getMetricLabels()).Inc()
+	labels := n.getMetricLabels()
+	labels["reason"] = commonAPI.ClassifyAbortReason(reason)
+	abortedBatchCount.With(labels).Inc()
+}
+
+// abortBatchWithFailure aborts the batch being processed and submits a failure indicating
+// commitment for it, so that the node does not miss the round.
+func (n *Node) abortBatchWithFailure(
+	roundCtx context.Context,
+	lastHeader *block.Header,
+	processed *processedBatch,
+	reason error,
+) {
+	if state, ok := n.state.(StateProcessingBatch); ok {
+		n.abortBatch(&state, reason)
+	}
+	n.transitionState(StateWaitingForBatch{})
+
+	commit := &commitment.ExecutorCommitment{
+		NodeID: n.commonNode.Identity.NodeSigner.Public(),
+		Header: commitment.ExecutorCommitmentHeader{
+			SchedulerID: processed.proposal.NodeID,
+			Header: commitment.ComputeResultsHeader{
+				Round:        lastHeader.Round + 1,
+				PreviousHash: lastHeader.EncodedHash(),
+			},
+		},
+	}
+	commit.Header.SetFailure(commitment.FailureUnknown)
+
+	n.logger.Debug("submitting failure indicating commitment",
+		"commitment", commit,
+	)
+	if err := n.signAndSubmitCommitment(roundCtx, commit); err != nil {
+		n.logger.Error("failed to sign and submit the commitment",
+			"commit", commit,
+			"err", err,
+		)
+		return
+	}
+
+	n.submitted[processed.rank] = struct{}{}
 }
 
 func (n *Node) proposeBatch(
@@ -819,8 +1103,18 @@ func (n *Node) proposeBatch(
 
 	batch := processed.computed
 
+	// Make sure the runtime returned a well-formed header before touching storage.
+	if err := validateComputedHeader(lastHeader, &batch.Header); err != nil {
+		n.logger.Error("invalid compute results header, aborting batch",
+			"err", err,
+		)
+		n.abortBatchWithFailure(roundCtx, lastHeader, processed, commonAPI.ErrAbortInvalidResults)
+		return
+	}
+
 	n.logger.Debug("proposing batch",
 		"scheduler_id", processed.proposal.NodeID,
+		"batch_hash", processed.proposal.Header.BatchHash,
 		"node_id", n.commonNode.Identity.NodeSigner.Public(),
 		"batch_size", len(processed.raw),
 		"io_root", *batch.Header.IORoot,
@@ -845,6 +1139,16 @@ func (n *Node) proposeBatch(
 		ec.Messages = batch.Messages
 	}
 
+	// Verify the RAK signature locally, as otherwise we would only find out that the runtime
+	// returned a malformed signature once the commitment gets rejected.
+	if err := n.verifyRakSig(&ec.Header); err != nil {
+		n.logger.Error("failed to verify RAK signature, aborting batch",
+			"err", err,
+		)
+		n.abortBatchWithFailure(roundCtx, lastHeader, processed, commonAPI.ErrAbortInvalidResults)
+		return
+	}
+
 	inputRoot := processed.proposal.Header.BatchHash
 
 	// Commit I/O and state write logs to storage.
@@ -914,14 +1218,14 @@ func (n *Node) proposeBatch(
 			"commit", ec,
 			"err", err,
 		)
-		n.abortBatch(&state)
+		n.abortBatch(&state, commonAPI.ErrAbortSubmitFailed)
 		return
 	}
 
 	n.submitted[processed.rank] = struct{}{}
 
 	if storageErr != nil {
-		n.abortBatch(&state)
+		n.abortBatch(&state, commonAPI.ErrAbortStorageFailed)
 		n.transitionState(StateWaitingForBatch{})
 		return
 	}
@@ -947,6 +1251,44 @@ func (n *Node) proposeBatch(
 	crash.Here(crashPointBatchProposeAfter)
 }
 
+// verifyRakSig verifies the RAK signature of the given commitment header against the RAK of the
+// hosted runtime. In case the runtime is not running inside a TEE, this is a no-op.
+func (n *Node) verifyRakSig(eh *commitment.ExecutorCommitmentHeader) error {
+	capabilityTEE, err := n.rt.GetCapabilityTEE()
+	if err != nil {
+		return fmt.Errorf("failed to retrieve runtime CapabilityTEE: %w", err)
+	}
+	return verifyRakSig(capabilityTEE, eh)
+}
+
+func verifyRakSig(capabilityTEE *node.CapabilityTEE, eh *commitment.ExecutorCommitmentHeader) error {
+	if capabilityTEE == nil {
+		return nil
+	}
+	if err := eh.VerifyRAK(capabilityTEE.RAK); err != nil {
+		return fmt.Errorf("%w: %s", errInvalidRakSig, err)
+	}
+	return nil
+}
+
+func validateComputedHeader(lastHeader *block.Header, h *commitment.ComputeResultsHeader) error {
+	switch {
+	case h.IORoot == nil:
+		return fmt.Errorf("%w: missing IO root", errInvalidHeader)
+	case h.StateRoot == nil:
+		return fmt.Errorf("%w: missing state root", errInvalidHeader)
+	case h.MessagesHash == nil:
+		return fmt.Errorf("%w: missing messages hash", errInvalidHeader)
+	case h.InMessagesHash == nil:
+		return fmt.Errorf("%w: missing incoming messages hash", errInvalidHeader)
+	case !h.IsParentOf(lastHeader):
+		return fmt.Errorf("%w: header is not a child of the latest block (round: %d, latest round: %d)",
+			errInvalidHeader, h.Round, lastHeader.Round,
+		)
+	}
+	return nil
+}
+
 func (n *Node) signAndSubmitCommitment(roundCtx context.Context, ec *commitment.ExecutorCommitment) error {
 	err := ec.Sign(n.commonNode.Identity.NodeSigner, n.commonNode.Runtime.ID())
 	if err != nil {
@@ -975,10 +1317,14 @@ func (n *Node) signAndSubmitCommitment(roundCtx context.Context, ec *commitment.
 }
 
 func (n *Node) processProposal(ctx context.Context, proposal *commitment.Proposal, rank uint64, discrepancy bool) {
-	n.logger.Debug("trying to process a proposal",
+	// Tag all log lines with the proposal, so that they can be correlated across nodes.
+	logger := n.logger.With(
 		"scheduler", proposal.NodeID,
 		"round", proposal.Header.Round,
+		"batch_hash", proposal.Header.BatchHash,
 		"rank", rank,
+	)
+	logger.Debug("trying to process a proposal",
 		"discrepancy", discrepancy,
 	)
 
@@ -986,7 +1332,7 @@ func (n *Node) processProposal(ctx context.Context, proposal *commitment.Proposa
 	switch n.state.(type) {
 	case StateWaitingForBatch:
 	default:
-		n.logger.Debug("not processing, invalid state",
+		logger.Debug("not processing, invalid state",
 			"state", n.state.Name(),
 		)
 		return
@@ -994,7 +1340,7 @@ func (n *Node) processProposal(ctx context.Context, proposal *commitment.Proposa
 
 	// Process only once.
 	if _, ok := n.submitted[rank]; ok {
-		n.logger.Debug("not processing, commitment already submitted")
+		logger.Debug("not processing, commitment already submitted")
 		return
 	}
 
@@ -1002,7 +1348,7 @@ func (n *Node) processProposal(ctx context.Context, proposal *commitment.Proposa
 	case true:
 		// Only backup executor workers are permitted to process batches.
 		if !n.epoch.IsExecutorBackupWorker() {
-			n.logger.Debug("not processing, not a backup executor")
+			logger.Debug("not processing, not a backup executor")
 			return
 		}
 	case false:
@@ -1010,7 +1356,7 @@ func (n *Node) processProposal(ctx context.Context, proposal *commitment.Proposa
 		// execution workers will wait for a discrepancy event before beginning execution.
 	}
 
-	n.logger.Debug("attempting to resolve batch")
+	logger.Debug("attempting to resolve batch")
 
 	// Try to resolve the batch first.
 	// TODO: Add metrics for how long it takes to receive the complete batch.
@@ -1048,7 +1394,7 @@ func (n *Node) processProposal(ctx context.Context, proposal *commitment.Proposa
 
 	// Missing transactions, we will wait until all are received.
 	if len(missingTxs) > 0 {
-		n.logger.Debug("some transactions are missing", "num_missing", len(missingTxs))
+		logger.Debug("some transactions are missing", "num_missing", len(missingTxs))
 
 		txHashes := maps.Keys(missingTxs)
 
@@ -1081,6 +1427,10 @@ func (n *Node) processProposal(ctx context.Context, proposal *commitment.Proposa
 	// Maybe process if we have the correct block.
 	currentHash := n.blockInfo.RuntimeBlock.Header.EncodedHash()
 	if !currentHash.Equal(&proposal.Header.PreviousHash) {
+		logger.Debug("not processing, proposal not for the current block",
+			"current_hash", currentHash,
+			"previous_hash", proposal.Header.PreviousHash,
+		)
 		return
 	}
 
@@ -1118,6 +1468,15 @@ func (n *Node) nudgeAvailabilityLocked(force bool) {
 			break
 		}
 
+		// Make sure we do not register with an attestation that the registry would reject as
+		// stale. Once the runtime updates its CapabilityTEE, availability will be nudged again.
+		if rt := n.commonNode.GetHostedRuntime(); rt != nil && n.commonNode.CurrentBlock != nil {
+			height := uint64(n.commonNode.CurrentBlockHeight)
+			if !n.ensureFreshAttestationLocked(rt, height, n.maxAttestationAgeLocked()) {
+				break
+			}
+		}
+
 		n.roleProvider.SetAvailable(n.commonNode.RegisterNodeRuntime)
 	default:
 		// Executor is not ready to process requests.
@@ -1129,7 +1488,111 @@ func (n *Node) nudgeAvailabilityLocked(force bool) {
 	}
 }
 
+// maxAttestationAgeLocked returns the maximum attestation age (in blocks) allowed by the SGX
+// constraints of the active runtime deployment. Zero means that attestation age is not enforced.
+//
+// As both the active descriptor and the registry parameters only change on epoch transitions, the
+// result is cached for the current epoch.
+func (n *Node) maxAttestationAgeLocked() uint64 {
+	epoch := n.commonNode.CurrentEpoch
+	if n.maxAttestationAgeEpoch == epoch {
+		return n.maxAttestationAge
+	}
+
+	dsc := n.commonNode.CurrentDescriptor
+	if dsc == nil || dsc.TEEHardware != node.TEEHardwareIntelSGX {
+		n.maxAttestationAge, n.maxAttestationAgeEpoch = 0, epoch
+		return 0
+	}
+
+	params, err := n.commonNode.Consensus.Registry().ConsensusParameters(n.ctx, n.commonNode.CurrentBlockHeight)
+	if err != nil {
+		n.logger.Warn("failed to query registry parameters",
+			"err", err,
+		)
+		return 0
+	}
+
+	maxAge, err := sgxMaxAttestationAge(dsc, epoch, params)
+	if err != nil {
+		n.logger.Warn("failed to determine maximum attestation age",
+			"err", err,
+		)
+		return 0
+	}
+	n.maxAttestationAge, n.maxAttestationAgeEpoch = maxAge, epoch
+	return maxAge
+}
+
+// sgxMaxAttestationAge returns the maximum attestation age (in blocks) required by the SGX
+// constraints of the runtime deployment active in the given epoch, with registry defaults applied.
+func sgxMaxAttestationAge(dsc *registry.Runtime, epoch beacon.EpochTime, params *registry.ConsensusParameters) (uint64, error) {
+	vi := dsc.ActiveDeployment(epoch)
+	if vi == nil {
+		return 0, nil
+	}
+
+	var sc node.SGXConstraints
+	if err := cbor.Unmarshal(vi.TEE, &sc); err != nil {
+		return 0, fmt.Errorf("malformed SGX constraints: %w", err)
+	}
+	if params.TEEFeatures != nil {
+		params.TEEFeatures.SGX.ApplyDefaultConstraints(&sc)
+	}
+	return sc.MaxAttestationAge, nil
+}
+
+// ensureFreshAttestationLocked checks whether the runtime's TEE attestation is not older than the
+// given maximum age at the given consensus height. In case it is, the runtime is asked to update
+// its CapabilityTEE and false is returned.
+func (n *Node) ensureFreshAttestationLocked(rt host.Runtime, height uint64, maxAge uint64) bool {
+	if maxAge == 0 {
+		return true
+	}
+
+	capabilityTEE, err := rt.GetCapabilityTEE()
+	if err != nil {
+		n.logger.Warn("failed to get CapabilityTEE for hosted runtime",
+			"err", err,
+		)
+		return true
+	}
+	age, ok := attestationAge(capabilityTEE, height)
+	if !ok || age <= maxAge {
+		return true
+	}
+
+	n.logger.Info("runtime attestation too old, requesting CapabilityTEE update",
+		"age", age,
+		"max_age", maxAge,
+	)
+
+	rt.UpdateCapabilityTEE()
+	return false
+}
+
+// attestationAge returns the age (in blocks) of the attestation contained in the given
+// CapabilityTEE at the given consensus height. The second return value is false in case the age
+// cannot be determined (e.g., the runtime is not running in a TEE).
+func attestationAge(capabilityTEE *node.CapabilityTEE, height uint64) (uint64, bool) {
+	if capabilityTEE == nil || capabilityTEE.Hardware != node.TEEHardwareIntelSGX {
+		return 0, false
+	}
+
+	var sa node.SGXAttestation
+	if err := cbor.Unmarshal(capabilityTEE.Attestation, &sa); err != nil {
+		return 0, false
+	}
+	// Unversioned attestations do not include the height.
+	if sa.V == 0 || sa.Height > height {
+		return 0, false
+	}
+	return height - sa.Height, true
+}
+
 func (n *Node) HandleRuntimeHostEventLocked(ev *host.Event) {
+	force := true
+
 	switch {
 	case ev.Started != nil:
 		// Make sure the runtime supports all the required features.
@@ -1162,6 +1625,13 @@ func (n *Node) HandleRuntimeHostEventLocked(ev *host.Event) {
 	case ev.Updated != nil:
 		// Update runtime capabilities.
 		n.runtimeReady = true
+
+		// Only force re-registration in case the CapabilityTEE has actually changed.
+		force = ev.Updated.Changed != 0
+	case ev.Stopped != nil && ev.Stopped.Idle:
+		// Runtime was stopped due to being idle, but it is started again on the next call so we
+		// can still service requests.
+		return
 	case ev.FailedToStart != nil, ev.Stopped != nil:
 		// Runtime failed to start or was stopped -- we can no longer service requests.
 		n.runtimeReady = false
@@ -1170,6 +1640,8 @@ func (n *Node) HandleRuntimeHostEventLocked(ev *host.Event) {
 		n.cancelRuntimeTrustSyncLocked()
 	case ev.ConfigUpdated != nil:
 		// Configuration updated, just refresh availability.
+	case ev.VersionMismatch != nil:
+		// Version mismatch, a failed to start event will follow.
 	default:
 		// Unknown event.
 		n.logger.Warn("unknown worker event",
@@ -1177,7 +1649,7 @@ func (n *Node) HandleRuntimeHostEventLocked(ev *host.Event) {
 		)
 	}
 
-	n.nudgeAvailabilityLocked(true)
+	n.nudgeAvailabilityLocked(force)
 }
 
 func (n *Node) handleProcessedBatch(ctx context.Context, batch *processedBatch) {
@@ -1195,34 +1667,7 @@ func (n *Node) handleProcessedBatch(ctx context.Context, batch *processedBatch)
 	// Check if there was an issue during batch processing.
 	if batch.computed == nil {
 		n.logger.Warn("worker has aborted batch processing")
-
-		n.abortBatch(&state)
-		n.transitionState(StateWaitingForBatch{})
-
-		commit := &commitment.ExecutorCommitment{
-			NodeID: n.commonNode.Identity.NodeSigner.Public(),
-			Header: commitment.ExecutorCommitmentHeader{
-				SchedulerID: batch.proposal.NodeID,
-				Header: commitment.ComputeResultsHeader{
-					Round:        lastHeader.Round + 1,
-					PreviousHash: lastHeader.EncodedHash(),
-				},
-			},
-		}
-		commit.Header.SetFailure(commitment.FailureUnknown)
-
-		n.logger.Debug("submitting failure indicating commitment",
-			"commitment", commit,
-		)
-		if err := n.signAndSubmitCommitment(ctx, commit); err != nil {
-			n.logger.Error("failed to sign and submit the commitment",
-				"commit", commit,
-				"err", err,
-			)
-			return
-		}
-
-		n.submitted[batch.rank] = struct{}{}
+		n.abortBatchWithFailure(ctx, &lastHeader, batch, commonAPI.ErrAbortRuntimeAborted)
 		return
 	}
 
@@ -1360,6 +1805,8 @@ func (n *Node) finalizePreviousRound() {
 		"header_type", n.blockInfo.RuntimeBlock.Header.HeaderType,
 	)
 
+	n.recordDiscrepancyResolution()
+
 	if n.proposedBatch != nil && n.blockInfo.RuntimeBlock.Header.HeaderType == block.Normal {
 		switch n.blockInfo.RuntimeBlock.Header.IORoot.Equal(&n.proposedBatch.proposedIORoot) {
 		case false:
@@ -1390,6 +1837,36 @@ func (n *Node) finalizePreviousRound() {
 	n.commonNode.TxPool.ClearProposedBatch()
 }
 
+// handleForceAbort aborts the batch that is currently being processed, if any.
+func (n *Node) handleForceAbort() {
+	state, ok := n.state.(StateProcessingBatch)
+	if !ok {
+		n.logger.Debug("ignoring abort request, not processing a batch",
+			"state", n.state,
+		)
+		return
+	}
+
+	n.logger.Warn("operator requested batch processing to be aborted")
+
+	// Signal the processing goroutine first and then abort the runtime, as the batch execution
+	// call is not bound to the round context and would otherwise keep running.
+	state.cancelFn(commonAPI.ErrAbortOperator)
+	if rt := n.commonNode.GetHostedRuntime(); rt != nil {
+		abortCtx, cancel := context.WithTimeout(n.ctx, abortTimeout)
+		defer cancel()
+
+		if err := rt.Abort(abortCtx, true); err != nil {
+			n.logger.Error("failed to abort the runtime",
+				"err", err,
+			)
+		}
+	}
+
+	n.abortBatch(&state, commonAPI.ErrAbortOperator)
+	n.transitionState(StateWaitingForBatch{})
+}
+
 // resetNodeState transitions to the StateWaitingForBatch state.
 func (n *Node) resetNodeState() {
 	switch state := n.state.(type) {
@@ -1404,7 +1881,7 @@ func (n *Node) resetNodeState() {
 		// Block finalized without the need for a backup worker.
 		n.logger.Info("considering the round finalized without backup worker")
 	case StateProcessingBatch:
-		n.abortBatch(&state)
+		n.abortBatch(&state, commonAPI.ErrAbortRoundEnded)
 	}
 
 	n.transitionState(StateWaitingForBatch{})
@@ -1421,6 +1898,7 @@ func (n *Node) drainChannels(ctx context.Context) {
 		case <-n.ecCh:
 		case <-n.evCh:
 		case <-n.reselectCh:
+		case <-n.abortCh:
 		case <-ctx.Done():
 			return
 		}
@@ -1429,6 +1907,8 @@ func (n *Node) drainChannels(ctx context.Context) {
 
 func (n *Node) worker() {
 	defer close(n.quitCh)
+	// Wait for any in-flight batch processing goroutines to finish after cancelling them.
+	defer n.batchWg.Wait()
 	defer (n.cancelCtx)()
 
 	// Wait for the common node to be initialized.
@@ -1680,6 +2160,9 @@ func (n *Node) roundWorker(ctx context.Context) {
 			flush = true
 		case <-n.reselectCh:
 			// Try again.
+		case <-n.abortCh:
+			// Abort the batch on operator request.
+			n.handleForceAbort()
 		}
 	}
 }
@@ -1697,24 +2180,26 @@ func NewNode(
 	ctx, cancel := context.WithCancel(context.Background())
 
 	n := &Node{
-		commonNode:       commonNode,
-		commonCfg:        commonCfg,
-		roleProvider:     roleProvider,
-		committeeTopic:   committeeTopic,
-		proposals:        newPendingProposals(),
-		ctx:              ctx,
-		cancelCtx:        cancel,
-		stopCh:           make(chan struct{}),
-		quitCh:           make(chan struct{}),
-		initCh:           make(chan struct{}),
-		state:            StateWaitingForBatch{},
-		txSync:           txsync.NewClient(commonNode.P2P, commonNode.ChainContext, commonNode.Runtime.ID()),
-		stateTransitions: pubsub.NewBroker(false),
-		blockInfoCh:      make(chan *runtime.BlockInfo, 1),
-		processedBatchCh: make(chan *processedBatch, 1),
-		reselectCh:       make(chan struct{}, 1),
-		missingTxCh:      make(chan [][]byte, 1),
-		logger:           logging.GetLogger("worker/executor/committee").With("runtime_id", commonNode.Runtime.ID()),
+		commonNode:             commonNode,
+		commonCfg:              commonCfg,
+		roleProvider:           roleProvider,
+		committeeTopic:         committeeTopic,
+		proposals:              newPendingProposals(),
+		ctx:                    ctx,
+		cancelCtx:              cancel,
+		stopCh:                 make(chan struct{}),
+		quitCh:                 make(chan struct{}),
+		initCh:                 make(chan struct{}),
+		state:                  StateWaitingForBatch{},
+		txSync:                 txsync.NewClient(commonNode.P2P, commonNode.ChainContext, commonNode.Runtime.ID()),
+		stateTransitions:       pubsub.NewBroker(false),
+		blockInfoCh:            make(chan *runtime.BlockInfo, 1),
+		processedBatchCh:       make(chan *processedBatch, 1),
+		reselectCh:             make(chan struct{}, 1),
+		abortCh:                make(chan struct{}, 1),
+		missingTxCh:            make(chan [][]byte, 1),
+		maxAttestationAgeEpoch: beacon.EpochInvalid,
+		logger:                 logging.GetLogger("worker/executor/committee").With("runtime_id", commonNode.Runtime.ID()),
 	}
 
 	// Register prune handler.
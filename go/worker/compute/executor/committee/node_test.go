@@ -0,0 +1,909 @@
+package committee
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
+	"github.com/oasisprotocol/oasis-core/go/common/version"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	cmt "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/api"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/commitment"
+	runtime "github.com/oasisprotocol/oasis-core/go/runtime/api"
+	"github.com/oasisprotocol/oasis-core/go/runtime/bundle"
+	"github.com/oasisprotocol/oasis-core/go/runtime/history"
+	"github.com/oasisprotocol/oasis-core/go/runtime/host"
+	"github.com/oasisprotocol/oasis-core/go/runtime/host/protocol"
+	"github.com/oasisprotocol/oasis-core/go/runtime/host/sandbox"
+	"github.com/oasisprotocol/oasis-core/go/runtime/host/sandbox/process"
+	runtimeRegistry "github.com/oasisprotocol/oasis-core/go/runtime/registry"
+	"github.com/oasisprotocol/oasis-core/go/runtime/txpool"
+	scheduler "github.com/oasisprotocol/oasis-core/go/scheduler/api"
+	storage "github.com/oasisprotocol/oasis-core/go/storage/api"
+	nodedb "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
+	"github.com/oasisprotocol/oasis-core/go/worker/common/committee"
+)
+
+type testRuntime struct {
+	runtimeRegistry.Runtime
+
+	id common.Namespace
+}
+
+func (r *testRuntime) ID() common.Namespace {
+	return r.id
+}
+
+func (r *testRuntime) History() history.History {
+	return history.NewNop(r.id)
+}
+
+type testTxPool struct {
+	txpool.TransactionPool
+}
+
+func (p *testTxPool) PromoteProposedBatch(batch []hash.Hash) ([]*txpool.TxQueueMeta, map[hash.Hash]int) {
+	return make([]*txpool.TxQueueMeta, len(batch)), nil
+}
+
+type testLocalStorage struct {
+	storage.LocalBackend
+}
+
+func (s *testLocalStorage) NodeDB() nodedb.NodeDB {
+	return &testNodeDB{}
+}
+
+type testNodeDB struct {
+	nodedb.NodeDB
+}
+
+func (db *testNodeDB) HasRoot(storage.Root) bool {
+	return true
+}
+
+func TestVerifyRakSig(t *testing.T) {
+	require := require.New(t)
+
+	rak := memorySigner.NewTestSigner("executor test RAK")
+	otherRak := memorySigner.NewTestSigner("executor test other RAK")
+	capabilityTEE := &node.CapabilityTEE{
+		Hardware: node.TEEHardwareIntelSGX,
+		RAK:      rak.Public(),
+	}
+
+	var ioRoot, stateRoot hash.Hash
+	ioRoot.FromBytes([]byte("io root"))
+	stateRoot.FromBytes([]byte("state root"))
+
+	eh := &commitment.ExecutorCommitmentHeader{
+		Header: commitment.ComputeResultsHeader{
+			Round:     42,
+			IORoot:    &ioRoot,
+			StateRoot: &stateRoot,
+		},
+	}
+
+	signHeader := func(signer signature.Signer) *signature.RawSignature {
+		sig, err := signature.Sign(signer, commitment.ComputeResultsHeaderSignatureContext, cbor.Marshal(eh.Header))
+		require.NoError(err, "Sign")
+		return &sig.Signature
+	}
+
+	// Runtimes not running inside a TEE are not verified.
+	err := verifyRakSig(nil, eh)
+	require.NoError(err, "verifyRakSig should skip runtimes without a TEE")
+
+	// Missing signature.
+	err = verifyRakSig(capabilityTEE, eh)
+	require.ErrorIs(err, errInvalidRakSig, "verifyRakSig should fail without a RAK signature")
+
+	// Valid signature.
+	eh.RAKSignature = signHeader(rak)
+	err = verifyRakSig(capabilityTEE, eh)
+	require.NoError(err, "verifyRakSig should succeed with a valid RAK signature")
+
+	// Signature made by a different RAK.
+	eh.RAKSignature = signHeader(otherRak)
+	err = verifyRakSig(capabilityTEE, eh)
+	require.ErrorIs(err, errInvalidRakSig, "verifyRakSig should fail with a bad RAK signature")
+
+	// Signature over a different header.
+	eh.RAKSignature = signHeader(rak)
+	eh.Header.Round++
+	err = verifyRakSig(capabilityTEE, eh)
+	require.ErrorIs(err, errInvalidRakSig, "verifyRakSig should fail for a modified header")
+}
+
+func TestWatchStateTransitionsWithCurrent(t *testing.T) {
+	require := require.New(t)
+
+	n := &Node{
+		state:            StateWaitingForBatch{},
+		stateTransitions: pubsub.NewBroker(false),
+		logger:           logging.GetLogger("worker/executor/committee/test"),
+	}
+
+	recvState := func(ch <-chan NodeState) NodeState {
+		select {
+		case state := <-ch:
+			return state
+		case <-time.After(time.Second):
+			t.Fatalf("failed to receive state")
+			return nil
+		}
+	}
+
+	// Subscribe before any transitions.
+	ch, sub := n.WatchStateTransitionsWithCurrent()
+	defer sub.Close()
+	require.Equal(StateWaitingForBatch{}, recvState(ch))
+
+	n.transitionState(StateWaitingForTxs{})
+	n.transitionState(StateWaitingForEvent{})
+	require.Equal(StateWaitingForTxs{}, recvState(ch))
+	require.Equal(StateWaitingForEvent{}, recvState(ch))
+
+	// Late subscribers should receive the current state first.
+	lateCh, lateSub := n.WatchStateTransitionsWithCurrent()
+	defer lateSub.Close()
+	require.Equal(StateWaitingForEvent{}, recvState(lateCh))
+
+	n.transitionState(StateWaitingForBatch{})
+	require.Equal(StateWaitingForBatch{}, recvState(lateCh))
+	require.Equal(StateWaitingForBatch{}, recvState(ch))
+}
+
+func TestHandleNewBlockSameRound(t *testing.T) {
+	require := require.New(t)
+
+	n := &Node{
+		blockInfoCh: make(chan *runtime.BlockInfo, 1),
+		logger:      logging.GetLogger("worker/executor/committee/test"),
+	}
+
+	newBlockInfo := func(round uint64) *runtime.BlockInfo {
+		return &runtime.BlockInfo{
+			RuntimeBlock: &block.Block{
+				Header: block.Header{Round: round},
+			},
+		}
+	}
+
+	// The first block should restart the round worker.
+	bi := newBlockInfo(1)
+	n.HandleNewBlockLocked(bi)
+	require.Equal(bi, <-n.blockInfoCh)
+
+	// Blocks for the same round should not restart the round worker.
+	n.HandleNewBlockLocked(newBlockInfo(1))
+	require.Empty(n.blockInfoCh, "blocks for the same round should be ignored")
+
+	// Blocks for the next round should restart the round worker.
+	bi = newBlockInfo(2)
+	n.HandleNewBlockLocked(bi)
+	require.Equal(bi, <-n.blockInfoCh)
+}
+
+func TestValidateComputedHeader(t *testing.T) {
+	require := require.New(t)
+
+	var emptyRoot hash.Hash
+	emptyRoot.Empty()
+
+	lastHeader := &block.Header{Round: 41}
+	lastHash := lastHeader.EncodedHash()
+
+	validHeader := func() *commitment.ComputeResultsHeader {
+		return &commitment.ComputeResultsHeader{
+			Round:          42,
+			PreviousHash:   lastHash,
+			IORoot:         &emptyRoot,
+			StateRoot:      &emptyRoot,
+			MessagesHash:   &emptyRoot,
+			InMessagesHash: &emptyRoot,
+		}
+	}
+
+	// Valid header.
+	err := validateComputedHeader(lastHeader, validHeader())
+	require.NoError(err, "validateComputedHeader should accept a valid header")
+
+	// Zero-value header.
+	err = validateComputedHeader(lastHeader, &commitment.ComputeResultsHeader{})
+	require.ErrorIs(err, errInvalidHeader, "validateComputedHeader should reject a zero-value header")
+
+	// Missing roots.
+	for _, unset := range []func(h *commitment.ComputeResultsHeader){
+		func(h *commitment.ComputeResultsHeader) { h.IORoot = nil },
+		func(h *commitment.ComputeResultsHeader) { h.StateRoot = nil },
+		func(h *commitment.ComputeResultsHeader) { h.MessagesHash = nil },
+		func(h *commitment.ComputeResultsHeader) { h.InMessagesHash = nil },
+	} {
+		h := validHeader()
+		unset(h)
+		err = validateComputedHeader(lastHeader, h)
+		require.ErrorIs(err, errInvalidHeader, "validateComputedHeader should reject missing roots")
+	}
+
+	// Wrong round.
+	h := validHeader()
+	h.Round = 43
+	err = validateComputedHeader(lastHeader, h)
+	require.ErrorIs(err, errInvalidHeader, "validateComputedHeader should reject a wrong round")
+
+	// Wrong previous hash.
+	h = validHeader()
+	h.PreviousHash = emptyRoot
+	err = validateComputedHeader(lastHeader, h)
+	require.ErrorIs(err, errInvalidHeader, "validateComputedHeader should reject a wrong previous hash")
+}
+
+func TestForceAbort(t *testing.T) {
+	require := require.New(t)
+
+	n := &Node{
+		state:            StateWaitingForBatch{},
+		stateTransitions: pubsub.NewBroker(false),
+		abortCh:          make(chan struct{}, 1),
+		logger:           logging.GetLogger("worker/executor/committee/test"),
+	}
+
+	// Multiple requests should be coalesced.
+	n.ForceAbort()
+	n.ForceAbort()
+	require.Len(n.abortCh, 1, "abort requests should be coalesced")
+	<-n.abortCh
+
+	// Requests should be ignored when not processing a batch.
+	for _, state := range []NodeState{
+		StateWaitingForBatch{},
+		StateWaitingForTxs{},
+		StateWaitingForEvent{},
+	} {
+		n.state = state
+		n.handleForceAbort()
+		require.Equal(state, n.state, "abort requests should be ignored when not processing a batch")
+	}
+
+	// Requests should abort the runtime when processing a batch, as the runtime call is not
+	// interrupted by cancelling the batch context.
+	done := make(chan struct{})
+	var aborted, forced bool
+	rt := &testHostRuntime{
+		onAbort: func(force bool) {
+			aborted, forced = true, force
+			close(done)
+		},
+	}
+	n.ctx = context.Background()
+	n.commonNode = newTestCommonNode(t, rt)
+	n.state = StateProcessingBatch{
+		cancelFn: func(error) {},
+		done:     done,
+	}
+	n.handleForceAbort()
+	require.True(aborted, "abort requests should abort the runtime")
+	require.True(forced, "abort requests should force a runtime restart")
+	require.Equal(StateWaitingForBatch{}, n.state, "abort requests should reset the state")
+}
+
+func TestInflightBatchGoroutines(t *testing.T) {
+	require := require.New(t)
+
+	n := &Node{
+		commonNode: &committee.Node{
+			Runtime: &testRuntime{id: common.NewTestNamespaceFromSeed([]byte("executor test"), 0)},
+		},
+		state:            StateWaitingForBatch{},
+		stateTransitions: pubsub.NewBroker(false),
+		processedBatchCh: make(chan *processedBatch, 1),
+		logger:           logging.GetLogger("worker/executor/committee/test"),
+	}
+	gauge := inflightBatchGoroutines.With(n.getMetricLabels())
+
+	var finished int
+	for i := 0; i < 5; i++ {
+		ctx, cancel := context.WithCancelCause(context.Background())
+		done := make(chan struct{})
+
+		state := StateProcessingBatch{
+			cancelFn: cancel,
+			done:     done,
+		}
+		n.transitionState(state)
+
+		n.goBatch(done, func() {
+			<-ctx.Done()
+			finished++
+		})
+		require.EqualValues(1, testutil.ToFloat64(gauge), "gauge should track the in-flight goroutine")
+
+		n.abortBatch(&state, errors.New("test abort"))
+		n.transitionState(StateWaitingForBatch{})
+	}
+
+	n.batchWg.Wait()
+	require.Equal(5, finished, "all batch goroutines should finish")
+	require.EqualValues(0, testutil.ToFloat64(gauge), "gauge should return to zero")
+}
+
+func TestBackupWorkerActivation(t *testing.T) {
+	require := require.New(t)
+
+	id := common.NewTestNamespaceFromSeed([]byte("executor backup worker test"), 0)
+	blk := block.NewGenesisBlock(id, 0)
+	proposal := &commitment.Proposal{
+		Header: commitment.ProposalHeader{
+			Round:        blk.Header.Round + 1,
+			PreviousHash: blk.Header.EncodedHash(),
+		},
+	}
+	discrepancy := &roothash.Event{
+		Height: 1,
+		ExecutionDiscrepancyDetected: &roothash.ExecutionDiscrepancyDetectedEvent{
+			Round: proposal.Header.Round,
+		},
+	}
+
+	// newBackupWorker creates a node that is only a backup worker in the current round. As the
+	// runtime history is not available, batch processing fails right after activation.
+	newBackupWorker := func() *Node {
+		return &Node{
+			commonNode: &committee.Node{
+				Runtime: &testRuntime{id: id},
+				TxPool:  &testTxPool{},
+			},
+			storage: &testLocalStorage{},
+			rt:      host.NewRichRuntime(&testHostRuntime{}),
+			epoch: committee.NewMockEpochSnapshot(0, nil, &committee.CommitteeInfo{
+				Roles: []scheduler.Role{scheduler.RoleBackupWorker},
+			}),
+			blockInfo: &runtime.BlockInfo{
+				RuntimeBlock:   blk,
+				ConsensusBlock: &consensus.LightBlock{Height: 1},
+				ActiveDescriptor: &registry.Runtime{
+					TxnScheduler: registry.TxnSchedulerParameters{MaxBatchSize: 1},
+				},
+			},
+			submitted:        make(map[uint64]struct{}),
+			state:            StateWaitingForBatch{},
+			stateTransitions: pubsub.NewBroker(false),
+			processedBatchCh: make(chan *processedBatch, 1),
+			logger:           logging.GetLogger("worker/executor/committee/test"),
+		}
+	}
+	// runRound performs a single iteration of the round worker loop, followed by the given event.
+	runRound := func(n *Node, ev *roothash.Event) {
+		ctx := context.Background()
+		switch n.discrepancy {
+		case nil:
+			n.updateState(ctx, 0, 0, false)
+			n.processProposal(ctx, proposal, 0, false)
+		default:
+			n.updateState(ctx, n.discrepancy.rank, n.discrepancy.rank, true)
+			n.processProposal(ctx, proposal, 0, true)
+		}
+		if ev != nil {
+			n.handleEvent(ctx, ev)
+		}
+		n.batchWg.Wait()
+	}
+
+	// Discrepancy event received before the batch.
+	n := newBackupWorker()
+	activations := backupWorkerActivationCount.With(n.getMetricLabels())
+	before := testutil.ToFloat64(activations)
+
+	n.handleEvent(context.Background(), discrepancy)
+	require.NotNil(n.discrepancy, "discrepancy should be detected")
+	runRound(n, nil)
+	require.EqualValues(ProcessingBatch, n.state.Name(), "backup worker should process the batch")
+	runRound(n, nil)
+	require.Equal(before+1, testutil.ToFloat64(activations), "activation should be counted once")
+
+	// Batch received before the discrepancy event.
+	n = newBackupWorker()
+	before = testutil.ToFloat64(activations)
+
+	runRound(n, nil)
+	require.EqualValues(WaitingForEvent, n.state.Name(), "backup worker should wait for a discrepancy")
+	require.Equal(before, testutil.ToFloat64(activations), "backup worker should not activate without a discrepancy")
+	runRound(n, discrepancy)
+	runRound(n, nil)
+	require.EqualValues(ProcessingBatch, n.state.Name(), "backup worker should process the batch")
+	runRound(n, nil)
+	require.Equal(before+1, testutil.ToFloat64(activations), "activation should be counted once")
+}
+
+func TestReplayBatchGenesis(t *testing.T) {
+	require := require.New(t)
+
+	n := &Node{
+		logger: logging.GetLogger("worker/executor/committee/test"),
+	}
+
+	_, err := n.ReplayBatch(context.Background(), 0, hash.Hash{})
+	require.Error(err, "ReplayBatch should reject the genesis round")
+}
+
+func TestRecordDiscrepancyResolution(t *testing.T) {
+	require := require.New(t)
+
+	n := &Node{
+		commonNode: &committee.Node{
+			Runtime: &testRuntime{id: common.NewTestNamespaceFromSeed([]byte("executor discrepancy test"), 0)},
+		},
+		logger: logging.GetLogger("worker/executor/committee/test"),
+	}
+	counter := func(outcome string) float64 {
+		labels := n.getMetricLabels()
+		labels["outcome"] = outcome
+		return testutil.ToFloat64(discrepancyResolutionCount.With(labels))
+	}
+
+	var ioRoot, otherIORoot hash.Hash
+	ioRoot.FromBytes([]byte("io root"))
+	otherIORoot.FromBytes([]byte("other io root"))
+
+	for _, tc := range []struct {
+		name        string
+		discrepancy *discrepancyEvent
+		headerType  block.HeaderType
+		proposed    *proposedBatch
+		outcome     string
+	}{
+		{"no discrepancy", nil, block.Normal, &proposedBatch{proposedIORoot: ioRoot}, ""},
+		{"stale discrepancy", &discrepancyEvent{round: 41}, block.Normal, &proposedBatch{proposedIORoot: ioRoot}, ""},
+		{"no own results", &discrepancyEvent{round: 42}, block.Normal, nil, ""},
+		{"agreed", &discrepancyEvent{round: 42}, block.Normal, &proposedBatch{proposedIORoot: ioRoot}, discrepancyOutcomeAgreed},
+		{"disagreed", &discrepancyEvent{round: 42}, block.Normal, &proposedBatch{proposedIORoot: otherIORoot}, discrepancyOutcomeDisagreed},
+		{"timed out", &discrepancyEvent{round: 42}, block.RoundFailed, nil, discrepancyOutcomeTimedOut},
+	} {
+		n.discrepancy = tc.discrepancy
+		n.proposedBatch = tc.proposed
+		n.blockInfo = &runtime.BlockInfo{
+			RuntimeBlock: &block.Block{
+				Header: block.Header{
+					Round:      42,
+					HeaderType: tc.headerType,
+					IORoot:     ioRoot,
+				},
+			},
+		}
+
+		before := make(map[string]float64)
+		for _, outcome := range []string{discrepancyOutcomeAgreed, discrepancyOutcomeDisagreed, discrepancyOutcomeTimedOut} {
+			before[outcome] = counter(outcome)
+		}
+
+		n.recordDiscrepancyResolution()
+
+		for outcome, value := range before {
+			expected := value
+			if outcome == tc.outcome {
+				expected++
+			}
+			require.Equal(expected, counter(outcome), "%s: outcome %s", tc.name, outcome)
+		}
+	}
+}
+
+type testHostRuntime struct {
+	host.Runtime
+
+	id       common.Namespace
+	notifier *pubsub.Broker
+
+	capabilityTEE *node.CapabilityTEE
+	updates       int
+
+	onAbort func(force bool)
+}
+
+func (r *testHostRuntime) ID() common.Namespace {
+	return r.id
+}
+
+func (r *testHostRuntime) WatchEvents() (<-chan *host.Event, pubsub.ClosableSubscription) {
+	typedCh := make(chan *host.Event)
+	sub := r.notifier.Subscribe()
+	sub.Unwrap(typedCh)
+
+	return typedCh, sub
+}
+
+func (r *testHostRuntime) WatchEventsFiltered(kinds ...host.EventKind) (<-chan *host.Event, pubsub.ClosableSubscription) {
+	ch, sub := r.WatchEvents()
+	return host.FilterEvents(ch, sub, kinds...)
+}
+
+func (r *testHostRuntime) Start() {
+}
+
+func (r *testHostRuntime) Abort(_ context.Context, force bool) error {
+	if r.onAbort != nil {
+		r.onAbort(force)
+	}
+	return nil
+}
+
+func (r *testHostRuntime) Call(context.Context, *protocol.Body) (*protocol.Body, error) {
+	return nil, errors.New("not supported")
+}
+
+func (r *testHostRuntime) GetCapabilityTEE() (*node.CapabilityTEE, error) {
+	return r.capabilityTEE, nil
+}
+
+func (r *testHostRuntime) UpdateCapabilityTEE() {
+	r.updates++
+}
+
+type testHostedRuntime struct {
+	testRuntime
+
+	rt host.Runtime
+}
+
+func (r *testHostedRuntime) RegistryDescriptor(context.Context) (*registry.Runtime, error) {
+	return &registry.Runtime{}, nil
+}
+
+func (r *testHostedRuntime) Host() (map[version.Version]*host.Config, host.Provisioner, error) {
+	return map[version.Version]*host.Config{{}: {}}, &testProvisioner{rt: r.rt}, nil
+}
+
+type testProvisioner struct {
+	host.Provisioner
+
+	rt host.Runtime
+}
+
+func (p *testProvisioner) NewRuntime(host.Config) (host.Runtime, error) {
+	return p.rt, nil
+}
+
+type testRuntimeHostFactory struct {
+	rt runtimeRegistry.Runtime
+}
+
+func (f *testRuntimeHostFactory) GetRuntime() runtimeRegistry.Runtime {
+	return f.rt
+}
+
+func (f *testRuntimeHostFactory) NewRuntimeHostHandler() protocol.Handler {
+	return nil
+}
+
+func (f *testRuntimeHostFactory) NewRuntimeHostNotifier(context.Context, host.Runtime) protocol.Notifier {
+	return nil
+}
+
+// newTestCommonNode creates a common committee node hosting the given runtime.
+func newTestCommonNode(t *testing.T, rt *testHostRuntime) *committee.Node {
+	rt.id = common.NewTestNamespaceFromSeed([]byte("executor hosted runtime test"), 0)
+	rt.notifier = pubsub.NewBroker(false)
+
+	return newTestHostingNode(t, rt)
+}
+
+// newTestHostingNode creates a common committee node hosting the given provisioned runtime.
+func newTestHostingNode(t *testing.T, rt host.Runtime) *committee.Node {
+	require := require.New(t)
+
+	hostedRt := &testHostedRuntime{testRuntime: testRuntime{id: rt.ID()}, rt: rt}
+
+	rhn, err := runtimeRegistry.NewRuntimeHostNode(&testRuntimeHostFactory{rt: hostedRt})
+	require.NoError(err, "NewRuntimeHostNode")
+	_, _, err = rhn.ProvisionHostedRuntime(context.Background())
+	require.NoError(err, "ProvisionHostedRuntime")
+	require.NoError(rhn.SetHostedRuntimeVersion(version.Version{}, nil), "SetHostedRuntimeVersion")
+
+	return &committee.Node{
+		RuntimeHostNode: rhn,
+		Runtime:         hostedRt,
+	}
+}
+
+func TestSGXMaxAttestationAge(t *testing.T) {
+	require := require.New(t)
+
+	dsc := &registry.Runtime{
+		TEEHardware: node.TEEHardwareIntelSGX,
+		Deployments: []*registry.VersionInfo{
+			{
+				ValidFrom: 0,
+				TEE: cbor.Marshal(&node.SGXConstraints{
+					Versioned: cbor.NewVersioned(1),
+				}),
+			},
+			{
+				ValidFrom: 10,
+				TEE: cbor.Marshal(&node.SGXConstraints{
+					Versioned:         cbor.NewVersioned(1),
+					MaxAttestationAge: 50,
+				}),
+			},
+		},
+	}
+	params := &registry.ConsensusParameters{
+		TEEFeatures: &node.TEEFeatures{
+			SGX: node.TEEFeaturesSGX{
+				DefaultMaxAttestationAge: 1200,
+			},
+		},
+	}
+
+	maxAge, err := sgxMaxAttestationAge(dsc, 5, params)
+	require.NoError(err, "sgxMaxAttestationAge")
+	require.EqualValues(1200, maxAge, "registry default should apply when constraints do not set it")
+
+	maxAge, err = sgxMaxAttestationAge(dsc, 10, params)
+	require.NoError(err, "sgxMaxAttestationAge")
+	require.EqualValues(50, maxAge, "active deployment constraints should take precedence")
+
+	maxAge, err = sgxMaxAttestationAge(dsc, 5, &registry.ConsensusParameters{})
+	require.NoError(err, "sgxMaxAttestationAge")
+	require.EqualValues(0, maxAge, "age should not be enforced without constraints or defaults")
+
+	dsc.Deployments[0].TEE = []byte("invalid")
+	_, err = sgxMaxAttestationAge(dsc, 5, params)
+	require.Error(err, "malformed constraints should fail")
+}
+
+func TestEnsureFreshAttestation(t *testing.T) {
+	require := require.New(t)
+
+	attestation := cbor.Marshal(&node.SGXAttestation{
+		Versioned: cbor.NewVersioned(1),
+		Height:    100,
+	})
+	rt := &testHostRuntime{
+		capabilityTEE: &node.CapabilityTEE{
+			Hardware:    node.TEEHardwareIntelSGX,
+			Attestation: attestation,
+		},
+	}
+	n := &Node{
+		logger: logging.GetLogger("worker/executor/committee/test"),
+	}
+
+	require.True(n.ensureFreshAttestationLocked(rt, 150, 50), "attestation at max age should be fresh")
+	require.Equal(0, rt.updates)
+
+	require.False(n.ensureFreshAttestationLocked(rt, 151, 50), "aged attestation should not be fresh")
+	require.Equal(1, rt.updates, "aged attestation should request a CapabilityTEE update")
+
+	require.True(n.ensureFreshAttestationLocked(rt, 1000, 0), "check should be disabled with zero max age")
+	require.Equal(1, rt.updates)
+
+	rt.capabilityTEE = nil
+	require.True(n.ensureFreshAttestationLocked(rt, 1000, 50), "non-TEE runtimes should not be checked")
+	require.Equal(1, rt.updates)
+}
+
+type testConsensus struct {
+	consensus.Backend
+
+	registry *testRegistry
+}
+
+func (c *testConsensus) Registry() registry.Backend {
+	return c.registry
+}
+
+type testRegistry struct {
+	registry.Backend
+
+	params  *registry.ConsensusParameters
+	queries int
+}
+
+func (r *testRegistry) ConsensusParameters(context.Context, int64) (*registry.ConsensusParameters, error) {
+	r.queries++
+	return r.params, nil
+}
+
+func TestMaxAttestationAgeCached(t *testing.T) {
+	require := require.New(t)
+
+	reg := &testRegistry{
+		params: &registry.ConsensusParameters{
+			TEEFeatures: &node.TEEFeatures{
+				SGX: node.TEEFeaturesSGX{
+					DefaultMaxAttestationAge: 1200,
+				},
+			},
+		},
+	}
+	n := &Node{
+		commonNode: &committee.Node{
+			Consensus: &testConsensus{registry: reg},
+			CurrentDescriptor: &registry.Runtime{
+				TEEHardware: node.TEEHardwareIntelSGX,
+				Deployments: []*registry.VersionInfo{
+					{
+						TEE: cbor.Marshal(&node.SGXConstraints{
+							Versioned: cbor.NewVersioned(1),
+						}),
+					},
+				},
+			},
+			CurrentEpoch: 1,
+		},
+		maxAttestationAgeEpoch: beacon.EpochInvalid,
+		logger:                 logging.GetLogger("worker/executor/committee/test"),
+	}
+
+	require.EqualValues(1200, n.maxAttestationAgeLocked())
+	require.EqualValues(1200, n.maxAttestationAgeLocked())
+	require.Equal(1, reg.queries, "parameters should be queried once per epoch")
+
+	reg.params.TEEFeatures.SGX.DefaultMaxAttestationAge = 600
+	n.commonNode.CurrentEpoch = 2
+	require.EqualValues(600, n.maxAttestationAgeLocked(), "cache should be refreshed on epoch transitions")
+	require.Equal(2, reg.queries)
+
+	n.commonNode.CurrentDescriptor = &registry.Runtime{}
+	n.commonNode.CurrentEpoch = 3
+	require.EqualValues(0, n.maxAttestationAgeLocked(), "non-SGX runtimes should not enforce attestation age")
+	require.Equal(2, reg.queries, "parameters should not be queried for non-SGX runtimes")
+}
+
+type testRuntimeHandler struct {
+	version version.Version
+}
+
+func (h *testRuntimeHandler) Handle(_ context.Context, body *protocol.Body) (*protocol.Body, error) {
+	switch {
+	case body.RuntimeInfoRequest != nil:
+		return &protocol.Body{
+			RuntimeInfoResponse: &protocol.RuntimeInfoResponse{
+				ProtocolVersion: version.RuntimeHostProtocol,
+				RuntimeVersion:  h.version,
+			},
+		}, nil
+	case body.RuntimePingRequest != nil:
+		return &protocol.Body{Empty: &protocol.Empty{}}, nil
+	}
+	return nil, errors.New("method not supported")
+}
+
+func TestIdleRuntimeCall(t *testing.T) {
+	require := require.New(t)
+
+	id := common.NewTestNamespaceFromSeed([]byte("executor idle runtime test"), 0)
+	logger := logging.GetLogger("worker/executor/committee/test")
+
+	var (
+		connsLock sync.Mutex
+		conns     []protocol.Connection
+	)
+	defer func() {
+		connsLock.Lock()
+		defer connsLock.Unlock()
+		for _, c := range conns {
+			c.Close()
+		}
+	}()
+
+	// Use a process that does nothing and connect to the host in its place.
+	p, err := sandbox.New(sandbox.Config{
+		GetSandboxConfig: func(_ host.Config, socketPath, _ string) (process.Config, error) {
+			conn, err := net.Dial("unix", socketPath)
+			if err != nil {
+				return process.Config{}, err
+			}
+			pc, err := protocol.NewConnection(logger, id, &testRuntimeHandler{})
+			if err != nil {
+				return process.Config{}, err
+			}
+			if err = pc.InitGuest(conn); err != nil {
+				return process.Config{}, err
+			}
+
+			connsLock.Lock()
+			conns = append(conns, pc)
+			connsLock.Unlock()
+
+			return process.Config{
+				Path:   "/bin/sleep",
+				Args:   []string{"60"},
+				Stdout: io.Discard,
+				Stderr: io.Discard,
+			}, nil
+		},
+		HostInfo: &protocol.HostInfo{
+			ConsensusBackend:         cmt.BackendName,
+			ConsensusProtocolVersion: version.Versions.ConsensusProtocol,
+		},
+		IdleTimeout:       200 * time.Millisecond,
+		InsecureNoSandbox: true,
+	})
+	require.NoError(err, "sandbox.New")
+
+	rt, err := p.NewRuntime(host.Config{
+		Bundle: &host.RuntimeBundle{
+			Bundle: &bundle.Bundle{
+				Manifest: &bundle.Manifest{ID: id},
+			},
+		},
+	})
+	require.NoError(err, "NewRuntime")
+
+	n := &Node{
+		ctx:        context.Background(),
+		commonNode: newTestHostingNode(t, rt),
+		logger:     logger,
+	}
+	hostedRt := n.commonNode.GetHostedRuntime()
+	require.NotNil(hostedRt, "GetHostedRuntime")
+
+	// Observe the runtime the same way the executor does.
+	evCh, evSub := hostedRt.WatchEvents()
+	defer evSub.Close()
+
+	hostedRt.Start()
+	defer hostedRt.Stop()
+
+	select {
+	case ev := <-evCh:
+		require.NotNil(ev.Started, "runtime should start")
+	case <-time.After(5 * time.Second):
+		t.Fatalf("failed to receive runtime started event")
+	}
+
+	// Wait for the runtime process to be stopped due to being idle. The executor must not
+	// observe this as the runtime going away, as it would then stop issuing calls.
+	select {
+	case ev := <-evCh:
+		require.NotNil(ev.Stopped, "idle runtime process should be stopped")
+		require.True(ev.Stopped.Idle, "stop should be reported as idle")
+
+		n.runtimeReady = true
+		n.HandleRuntimeHostEventLocked(ev)
+		require.True(n.runtimeReady, "idle runtime should remain ready")
+	case <-time.After(5 * time.Second):
+		t.Fatalf("failed to receive runtime stopped event")
+	}
+	_, err = rt.GetProcessInfo()
+	require.Error(err, "idle runtime process should be stopped")
+
+	// Calls made by the executor should still be served.
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rtInfo, err := n.commonNode.GetHostedRuntime().GetInfo(ctx)
+	require.NoError(err, "GetInfo should be served after an idle stop")
+	require.NotNil(rtInfo)
+	_, err = n.commonNode.GetHostedRuntime().Call(ctx, &protocol.Body{RuntimePingRequest: &protocol.Empty{}})
+	require.NoError(err, "Call should be served after an idle stop")
+
+	// The executor should be notified that the runtime has been started again.
+	select {
+	case ev := <-evCh:
+		require.NotNil(ev.Started, "runtime should be started again")
+	case <-time.After(5 * time.Second):
+		t.Fatalf("failed to receive runtime started event")
+	}
+}
@@ -53,8 +53,13 @@ func WorkerImplementationTests(
 		testInitialEpochTransition(t, stateCh, beacon)
 	})
 
+	var blk *api.AnnotatedBlock
 	t.Run("QueueTx", func(t *testing.T) {
-		testQueueTx(t, runtimeID, stateCh, commonNode, rtNode, roothash, storage)
+		blk = testQueueTx(t, runtimeID, stateCh, commonNode, rtNode, roothash, storage)
+	})
+
+	t.Run("ReplayBatch", func(t *testing.T) {
+		testReplayBatch(t, rtNode, blk)
 	})
 
 	// TODO: Add more tests.
@@ -91,7 +96,7 @@ func testQueueTx(
 	_ *committee.Node,
 	roothash roothash.Backend,
 	st storage.Backend,
-) {
+) *api.AnnotatedBlock {
 	ctx := context.Background()
 
 	// Subscribe to roothash blocks.
@@ -150,6 +155,32 @@ func testQueueTx(
 	// Fetch the first non-empty block.
 	_, err = nextRuntimeBlock(blocksCh, true)
 	require.Error(t, err, "unexpected block as a result of a duplicate transaction")
+
+	return blk
+}
+
+func testReplayBatch(t *testing.T, rtNode *committee.Node, blk *api.AnnotatedBlock) {
+	require.NotNil(t, blk, "a block containing a batch should have been finalized")
+
+	// Replaying the batch should produce the same results as the live execution.
+	batch, err := rtNode.ReplayBatch(context.Background(), blk.Block.Header.Round, blk.Block.Header.IORoot)
+	require.NoError(t, err, "ReplayBatch")
+	require.EqualValues(t, blk.Block.Header.Round, batch.Header.Round, "replayed round should match")
+	require.EqualValues(t, blk.Block.Header.PreviousHash, batch.Header.PreviousHash, "replayed previous hash should match")
+	require.NotNil(t, batch.Header.IORoot, "replayed batch should have an I/O root")
+	require.EqualValues(t, blk.Block.Header.IORoot, *batch.Header.IORoot, "replayed I/O root should match")
+	require.NotNil(t, batch.Header.StateRoot, "replayed batch should have a state root")
+	require.EqualValues(t, blk.Block.Header.StateRoot, *batch.Header.StateRoot, "replayed state root should match")
+	require.NotNil(t, batch.Header.MessagesHash, "replayed batch should have a messages hash")
+	require.EqualValues(t, blk.Block.Header.MessagesHash, *batch.Header.MessagesHash, "replayed messages hash should match")
+	require.NotNil(t, batch.Header.InMessagesHash, "replayed batch should have an incoming messages hash")
+	require.EqualValues(t, blk.Block.Header.InMessagesHash, *batch.Header.InMessagesHash, "replayed incoming messages hash should match")
+
+	// Replaying a batch with unknown inputs should fail.
+	var ioRoot hash.Hash
+	ioRoot.FromBytes([]byte("unknown inputs"))
+	_, err = rtNode.ReplayBatch(context.Background(), blk.Block.Header.Round, ioRoot)
+	require.Error(t, err, "ReplayBatch should fail for unknown inputs")
 }
 
 // nextRuntimeBlock return the next (non-empty) runtime block.
@@ -405,6 +405,9 @@ func (w *Worker) handleRuntimeHostEvent(ev *host.Event) {
 			)
 			return nil
 		})
+	case ev.Stopped != nil && ev.Stopped.Idle:
+		// Runtime was stopped due to being idle, but it is started again on the next call so we
+		// can still service requests.
 	case ev.FailedToStart != nil, ev.Stopped != nil:
 		// We can no longer service requests.
 		w.roleProvider.SetUnavailable()